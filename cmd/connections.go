@@ -0,0 +1,453 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/url"
+	"sort"
+	"strings"
+
+	"github.com/hupe1980/mwaacli/pkg/mwaa"
+	"github.com/spf13/cobra"
+)
+
+// secretMask replaces secret connection fields in the output.
+const secretMask = "***"
+
+// newConnectionsCommand creates the root command for managing connections in MWAA.
+func newConnectionsCommand(globalOpts *globalOptions) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "connections",
+		Short: "Manage connections in MWAA",
+		Long:  `Manage connections stored in the Airflow database of Amazon Managed Workflows for Apache Airflow (MWAA).`,
+	}
+
+	cmd.AddCommand(newListConnectionsCommand(globalOpts))
+	cmd.AddCommand(newGetConnectionCommand(globalOpts))
+	cmd.AddCommand(newCreateConnectionCommand(globalOpts))
+	cmd.AddCommand(newUpdateConnectionCommand(globalOpts))
+	cmd.AddCommand(newDeleteConnectionCommand(globalOpts))
+	cmd.AddCommand(newTestConnectionCommand(globalOpts))
+
+	return cmd
+}
+
+// connectionOptions holds the flags describing the fields of a connection.
+type connectionOptions struct {
+	connType    string
+	description string
+	host        string
+	login       string
+	password    string
+	passwordIn  bool
+	schema      string
+	port        int
+	extra       string
+}
+
+// addFlags registers the connection field flags on the given command.
+func (o *connectionOptions) addFlags(cmd *cobra.Command) {
+	cmd.Flags().StringVar(&o.connType, "conn-type", "", "Connection type (e.g. postgres, http, aws)")
+	cmd.Flags().StringVar(&o.description, "description", "", "Description of the connection")
+	cmd.Flags().StringVar(&o.host, "host", "", "Host of the connection")
+	cmd.Flags().StringVar(&o.login, "login", "", "Login of the connection")
+	cmd.Flags().StringVar(&o.password, "password", "", "Password of the connection (visible in the shell history, prefer --password-stdin)")
+	cmd.Flags().BoolVar(&o.passwordIn, "password-stdin", false, "Read the password of the connection from stdin")
+	cmd.Flags().StringVar(&o.schema, "schema", "", "Schema of the connection")
+	cmd.Flags().IntVar(&o.port, "port", 0, "Port of the connection")
+	cmd.Flags().StringVar(&o.extra, "extra", "", "Extra parameters of the connection as a JSON object")
+}
+
+// fields returns the connection fields whose flags were set explicitly.
+func (o *connectionOptions) fields(cmd *cobra.Command) (map[string]any, error) {
+	fields := map[string]any{}
+
+	flagFields := map[string]struct {
+		key   string
+		value any
+	}{
+		"conn-type":   {"conn_type", o.connType},
+		"description": {"description", o.description},
+		"host":        {"host", o.host},
+		"login":       {"login", o.login},
+		"password":    {"password", o.password},
+		"schema":      {"schema", o.schema},
+		"port":        {"port", o.port},
+		"extra":       {"extra", o.extra},
+	}
+
+	for flag, field := range flagFields {
+		if cmd.Flags().Changed(flag) {
+			fields[field.key] = field.value
+		}
+	}
+
+	if o.passwordIn {
+		if cmd.Flags().Changed("password") {
+			return nil, fmt.Errorf("--password and --password-stdin cannot be used together")
+		}
+
+		password, err := io.ReadAll(cmd.InOrStdin())
+		if err != nil {
+			return nil, fmt.Errorf("failed to read password from stdin: %w", err)
+		}
+
+		fields["password"] = strings.TrimRight(string(password), "\r\n")
+	}
+
+	if o.extra != "" && !json.Valid([]byte(o.extra)) {
+		return nil, fmt.Errorf("invalid extra: not a valid JSON document")
+	}
+
+	return fields, nil
+}
+
+// newListConnectionsCommand creates the command to list connections.
+func newListConnectionsCommand(globalOpts *globalOptions) *cobra.Command {
+	var (
+		limit       int
+		offset      int
+		all         bool
+		orderBy     string
+		showSecrets bool
+		mwaaEnvName string
+	)
+
+	cmd := &cobra.Command{
+		Use:           "list",
+		Short:         "List connections in the database",
+		SilenceUsage:  true,
+		SilenceErrors: true,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			ctx := context.Background()
+
+			client, err := initMWAAClient(ctx, globalOpts, &mwaaEnvName)
+			if err != nil {
+				return err
+			}
+
+			queryParams := map[string]any{}
+
+			if orderBy != "" {
+				queryParams["order_by"] = orderBy
+			}
+
+			connections, err := fetchCollection(ctx, client, mwaaEnvName, "/connections", "connections", queryParams, limit, offset, all)
+			if err != nil {
+				return err
+			}
+
+			if !showSecrets {
+				for _, connection := range connections {
+					maskConnectionSecrets(connection)
+				}
+			}
+
+			return printJSON(cmd, connections)
+		},
+	}
+
+	cmd.Flags().IntVar(&limit, "limit", 100, "The number of items to return")
+	cmd.Flags().IntVar(&offset, "offset", 0, "The number of items to skip before starting to collect the result set")
+	cmd.Flags().BoolVar(&all, "all", false, "Fetch all pages of the result set, using --limit as page size")
+	cmd.Flags().StringVar(&orderBy, "order-by", "", "The name of the field to order the results by. Prefix a field name with - to reverse the sort order")
+	cmd.Flags().BoolVar(&showSecrets, "show-secrets", false, "Show secret fields (password, extra) in clear text")
+
+	cmd.Flags().StringVar(&mwaaEnvName, "env", "", "MWAA environment name")
+
+	return cmd
+}
+
+// newGetConnectionCommand creates the command to get a single connection.
+func newGetConnectionCommand(globalOpts *globalOptions) *cobra.Command {
+	var (
+		showSecrets bool
+		mwaaEnvName string
+	)
+
+	cmd := &cobra.Command{
+		Use:           "get [conn-id]",
+		Short:         "Get a connection",
+		SilenceUsage:  true,
+		SilenceErrors: true,
+		Args:          cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := context.Background()
+
+			client, err := initMWAAClient(ctx, globalOpts, &mwaaEnvName)
+			if err != nil {
+				return err
+			}
+
+			var response map[string]any
+			if err := client.RestAPIGet(ctx, mwaaEnvName, connectionPath(args[0]), nil, &response); err != nil {
+				return err
+			}
+
+			if !showSecrets {
+				maskConnectionSecrets(response)
+			}
+
+			return printJSON(cmd, response)
+		},
+	}
+
+	cmd.Flags().BoolVar(&showSecrets, "show-secrets", false, "Show secret fields (password, extra) in clear text")
+
+	cmd.Flags().StringVar(&mwaaEnvName, "env", "", "MWAA environment name")
+
+	return cmd
+}
+
+// newCreateConnectionCommand creates the command to create a connection.
+func newCreateConnectionCommand(globalOpts *globalOptions) *cobra.Command {
+	var (
+		opts        connectionOptions
+		showSecrets bool
+		mwaaEnvName string
+	)
+
+	cmd := &cobra.Command{
+		Use:           "create [conn-id]",
+		Short:         "Create a connection",
+		SilenceUsage:  true,
+		SilenceErrors: true,
+		Args:          cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			payload, err := opts.fields(cmd)
+			if err != nil {
+				return err
+			}
+
+			payload["connection_id"] = args[0]
+
+			ctx := context.Background()
+
+			client, err := initMWAAClient(ctx, globalOpts, &mwaaEnvName)
+			if err != nil {
+				return err
+			}
+
+			var response map[string]any
+			if err := client.RestAPIPost(ctx, mwaaEnvName, "/connections", nil, payload, &response); err != nil {
+				return err
+			}
+
+			if !showSecrets {
+				maskConnectionSecrets(response)
+			}
+
+			return printJSON(cmd, response)
+		},
+	}
+
+	opts.addFlags(cmd)
+	_ = cmd.MarkFlagRequired("conn-type")
+	cmd.Flags().BoolVar(&showSecrets, "show-secrets", false, "Show secret fields (password, extra) in clear text")
+
+	cmd.Flags().StringVar(&mwaaEnvName, "env", "", "MWAA environment name")
+
+	return cmd
+}
+
+// newUpdateConnectionCommand creates the command to update fields of a connection.
+func newUpdateConnectionCommand(globalOpts *globalOptions) *cobra.Command {
+	var (
+		opts        connectionOptions
+		showSecrets bool
+		mwaaEnvName string
+	)
+
+	cmd := &cobra.Command{
+		Use:           "update [conn-id]",
+		Short:         "Update fields of a connection",
+		Long:          "Update fields of a connection. Only the fields given as flags are changed.",
+		SilenceUsage:  true,
+		SilenceErrors: true,
+		Args:          cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			fields, err := opts.fields(cmd)
+			if err != nil {
+				return err
+			}
+
+			if len(fields) == 0 {
+				return fmt.Errorf("no fields to update, set at least one field flag")
+			}
+
+			updateMask := make([]string, 0, len(fields))
+			for key := range fields {
+				updateMask = append(updateMask, key)
+			}
+
+			sort.Strings(updateMask)
+
+			ctx := context.Background()
+
+			client, err := initMWAAClient(ctx, globalOpts, &mwaaEnvName)
+			if err != nil {
+				return err
+			}
+
+			// The API validates the payload as a full connection, which requires the connection type
+			if _, ok := fields["conn_type"]; !ok {
+				var current struct {
+					ConnType string `document:"conn_type"`
+				}
+				if err := client.RestAPIGet(ctx, mwaaEnvName, connectionPath(args[0]), nil, &current); err != nil {
+					return err
+				}
+
+				fields["conn_type"] = current.ConnType
+			}
+
+			fields["connection_id"] = args[0]
+
+			queryParams := map[string]any{
+				"update_mask": updateMask,
+			}
+
+			var response map[string]any
+			if err := client.RestAPIPatch(ctx, mwaaEnvName, connectionPath(args[0]), queryParams, fields, &response); err != nil {
+				return err
+			}
+
+			if !showSecrets {
+				maskConnectionSecrets(response)
+			}
+
+			return printJSON(cmd, response)
+		},
+	}
+
+	opts.addFlags(cmd)
+	cmd.Flags().BoolVar(&showSecrets, "show-secrets", false, "Show secret fields (password, extra) in clear text")
+
+	cmd.Flags().StringVar(&mwaaEnvName, "env", "", "MWAA environment name")
+
+	return cmd
+}
+
+// newDeleteConnectionCommand creates the command to delete a connection.
+func newDeleteConnectionCommand(globalOpts *globalOptions) *cobra.Command {
+	var mwaaEnvName string
+
+	cmd := &cobra.Command{
+		Use:           "delete [conn-id]",
+		Short:         "Delete a connection",
+		SilenceUsage:  true,
+		SilenceErrors: true,
+		Args:          cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := context.Background()
+
+			client, err := initMWAAClient(ctx, globalOpts, &mwaaEnvName)
+			if err != nil {
+				return err
+			}
+
+			if err := client.RestAPIDelete(ctx, mwaaEnvName, connectionPath(args[0]), nil); err != nil {
+				return err
+			}
+
+			cmd.Println(green("[SUCCESS]"), fmt.Sprintf("Connection %s deleted.", args[0]))
+
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&mwaaEnvName, "env", "", "MWAA environment name")
+
+	return cmd
+}
+
+// newTestConnectionCommand creates the command to test a connection.
+func newTestConnectionCommand(globalOpts *globalOptions) *cobra.Command {
+	var (
+		opts        connectionOptions
+		mwaaEnvName string
+	)
+
+	cmd := &cobra.Command{
+		Use:   "test [conn-id]",
+		Short: "Test a connection",
+		Long: `Test a connection from the MWAA environment. The stored connection is used as a base and
+can be overridden with the field flags. The API never returns passwords, so connections that need one
+must be tested with --password-stdin.`,
+		SilenceUsage:  true,
+		SilenceErrors: true,
+		Args:          cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			fields, err := opts.fields(cmd)
+			if err != nil {
+				return err
+			}
+
+			ctx := context.Background()
+
+			client, err := initMWAAClient(ctx, globalOpts, &mwaaEnvName)
+			if err != nil {
+				return err
+			}
+
+			payload := map[string]any{}
+
+			var stored map[string]any
+			if err := client.RestAPIGet(ctx, mwaaEnvName, connectionPath(args[0]), nil, &stored); err != nil {
+				if !mwaa.IsNotFound(err) {
+					return err
+				}
+			}
+
+			for key, value := range stored {
+				if value != nil {
+					payload[key] = value
+				}
+			}
+
+			for key, value := range fields {
+				payload[key] = value
+			}
+
+			payload["connection_id"] = args[0]
+
+			var response struct {
+				Status  bool   `document:"status"`
+				Message string `document:"message"`
+			}
+			if err := client.RestAPIPost(ctx, mwaaEnvName, "/connections/test", nil, payload, &response); err != nil {
+				return err
+			}
+
+			if !response.Status {
+				return fmt.Errorf("connection test failed: %s", response.Message)
+			}
+
+			cmd.Println(green("[SUCCESS]"), response.Message)
+
+			return nil
+		},
+	}
+
+	opts.addFlags(cmd)
+
+	cmd.Flags().StringVar(&mwaaEnvName, "env", "", "MWAA environment name")
+
+	return cmd
+}
+
+// maskConnectionSecrets replaces the secret fields of a connection with a mask.
+func maskConnectionSecrets(connection map[string]any) {
+	for _, key := range []string{"password", "extra"} {
+		if value, ok := connection[key]; ok && value != nil && value != "" {
+			connection[key] = secretMask
+		}
+	}
+}
+
+// connectionPath returns the REST API path of a connection.
+func connectionPath(connID string) string {
+	return fmt.Sprintf("/connections/%s", url.PathEscape(connID))
+}
@@ -0,0 +1,88 @@
+package cmd
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMaskConnectionSecrets(t *testing.T) {
+	connection := map[string]any{
+		"connection_id": "postgres_default",
+		"host":          "db.example.com",
+		"password":      "secret",
+		"extra":         `{"sslmode": "require"}`,
+	}
+
+	maskConnectionSecrets(connection)
+
+	assert.Equal(t, map[string]any{
+		"connection_id": "postgres_default",
+		"host":          "db.example.com",
+		"password":      secretMask,
+		"extra":         secretMask,
+	}, connection)
+
+	empty := map[string]any{"extra": nil, "password": ""}
+	maskConnectionSecrets(empty)
+	assert.Equal(t, map[string]any{"extra": nil, "password": ""}, empty)
+}
+
+func TestConnectionOptionsFields(t *testing.T) {
+	tests := []struct {
+		name        string
+		args        []string
+		stdin       string
+		expected    map[string]any
+		expectError bool
+	}{
+		{
+			name:     "Only changed flags",
+			args:     []string{"--host", "db.example.com", "--port", "5432"},
+			expected: map[string]any{"host": "db.example.com", "port": 5432},
+		},
+		{
+			name:     "Valid extra",
+			args:     []string{"--conn-type", "http", "--extra", `{"timeout": 10}`},
+			expected: map[string]any{"conn_type": "http", "extra": `{"timeout": 10}`},
+		},
+		{
+			name:     "Password from stdin",
+			args:     []string{"--login", "admin", "--password-stdin"},
+			stdin:    "s3cr3t\n",
+			expected: map[string]any{"login": "admin", "password": "s3cr3t"},
+		},
+		{
+			name:        "Password and password from stdin",
+			args:        []string{"--password", "s3cr3t", "--password-stdin"},
+			expectError: true,
+		},
+		{
+			name:        "Invalid extra",
+			args:        []string{"--extra", "{invalid"},
+			expectError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var opts connectionOptions
+
+			cmd := &cobra.Command{}
+			cmd.SetIn(strings.NewReader(tt.stdin))
+			opts.addFlags(cmd)
+			assert.NoError(t, cmd.ParseFlags(tt.args))
+
+			fields, err := opts.fields(cmd)
+
+			if tt.expectError {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.expected, fields)
+			}
+		})
+	}
+}
@@ -0,0 +1,335 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+// newDagRunsCommand creates the root command for managing DAG runs in MWAA.
+func newDagRunsCommand(globalOpts *globalOptions) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "dagruns",
+		Short: "Manage DAG runs in MWAA",
+		Long:  `Manage DAG runs in Amazon Managed Workflows for Apache Airflow (MWAA).`,
+	}
+
+	cmd.AddCommand(newListDagRunsCommand(globalOpts))
+	cmd.AddCommand(newGetDagRunCommand(globalOpts))
+	cmd.AddCommand(newClearDagRunCommand(globalOpts))
+	cmd.AddCommand(newSetDagRunStateCommand(globalOpts))
+	cmd.AddCommand(newDeleteDagRunCommand(globalOpts))
+
+	return cmd
+}
+
+// newListDagRunsCommand creates the command to list DAG runs.
+func newListDagRunsCommand(globalOpts *globalOptions) *cobra.Command {
+	var (
+		limit            int
+		offset           int
+		all              bool
+		orderBy          string
+		states           []string
+		executionDateGTE string
+		executionDateLTE string
+		startDateGTE     string
+		startDateLTE     string
+		mwaaEnvName      string
+	)
+
+	cmd := &cobra.Command{
+		Use:           "list [dag-id]",
+		Short:         "List DAG runs of a DAG or of all DAGs",
+		SilenceUsage:  true,
+		SilenceErrors: true,
+		Args:          cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			// "~" lists the DAG runs of all DAGs
+			dagID := "~"
+			if len(args) > 0 {
+				dagID = args[0]
+			}
+
+			queryParams := map[string]any{}
+
+			if orderBy != "" {
+				queryParams["order_by"] = orderBy
+			}
+
+			if len(states) > 0 {
+				queryParams["state"] = states
+			}
+
+			dateFilters := map[string]string{
+				"execution_date_gte": executionDateGTE,
+				"execution_date_lte": executionDateLTE,
+				"start_date_gte":     startDateGTE,
+				"start_date_lte":     startDateLTE,
+			}
+
+			for key, value := range dateFilters {
+				if value == "" {
+					continue
+				}
+
+				date, err := time.Parse(time.RFC3339, value)
+				if err != nil {
+					return fmt.Errorf("invalid %s format: %w", key, err)
+				}
+
+				queryParams[key] = date.Format(time.RFC3339)
+			}
+
+			ctx := context.Background()
+
+			client, err := initMWAAClient(ctx, globalOpts, &mwaaEnvName)
+			if err != nil {
+				return err
+			}
+
+			dagRuns, err := fetchCollection(ctx, client, mwaaEnvName, dagPath(dagID)+"/dagRuns", "dag_runs", queryParams, limit, offset, all)
+			if err != nil {
+				return err
+			}
+
+			return printJSON(cmd, dagRuns)
+		},
+	}
+
+	cmd.Flags().IntVar(&limit, "limit", 100, "The number of items to return")
+	cmd.Flags().IntVar(&offset, "offset", 0, "The number of items to skip before starting to collect the result set")
+	cmd.Flags().BoolVar(&all, "all", false, "Fetch all pages of the result set, using --limit as page size")
+	cmd.Flags().StringVar(&orderBy, "order-by", "", "The name of the field to order the results by. Prefix a field name with - to reverse the sort order")
+	cmd.Flags().StringSliceVar(&states, "state", nil, "List of states to filter results (e.g. queued,running,success,failed)")
+	cmd.Flags().StringVar(&executionDateGTE, "execution-date-gte", "", "Only return DAG runs with a logical date on or after this time (RFC3339)")
+	cmd.Flags().StringVar(&executionDateLTE, "execution-date-lte", "", "Only return DAG runs with a logical date on or before this time (RFC3339)")
+	cmd.Flags().StringVar(&startDateGTE, "start-date-gte", "", "Only return DAG runs started on or after this time (RFC3339)")
+	cmd.Flags().StringVar(&startDateLTE, "start-date-lte", "", "Only return DAG runs started on or before this time (RFC3339)")
+
+	cmd.Flags().StringVar(&mwaaEnvName, "env", "", "MWAA environment name")
+
+	return cmd
+}
+
+// newGetDagRunCommand creates the command to get details of a specific DAG run.
+func newGetDagRunCommand(globalOpts *globalOptions) *cobra.Command {
+	var mwaaEnvName string
+
+	cmd := &cobra.Command{
+		Use:           "get [dag-id] [run-id]",
+		Short:         "Get details of a specific DAG run",
+		SilenceUsage:  true,
+		SilenceErrors: true,
+		Args:          cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := context.Background()
+
+			client, err := initMWAAClient(ctx, globalOpts, &mwaaEnvName)
+			if err != nil {
+				return err
+			}
+
+			var response map[string]any
+			if err := client.RestAPIGet(ctx, mwaaEnvName, dagRunPath(args[0], args[1]), nil, &response); err != nil {
+				return err
+			}
+
+			return printJSON(cmd, response)
+		},
+	}
+
+	cmd.Flags().StringVar(&mwaaEnvName, "env", "", "MWAA environment name")
+
+	return cmd
+}
+
+// newClearDagRunCommand creates the command to clear a DAG run.
+func newClearDagRunCommand(globalOpts *globalOptions) *cobra.Command {
+	var (
+		dryRun      bool
+		mwaaEnvName string
+	)
+
+	cmd := &cobra.Command{
+		Use:           "clear [dag-id] [run-id]",
+		Short:         "Clear a DAG run so that its task instances are run again",
+		SilenceUsage:  true,
+		SilenceErrors: true,
+		Args:          cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := context.Background()
+
+			client, err := initMWAAClient(ctx, globalOpts, &mwaaEnvName)
+			if err != nil {
+				return err
+			}
+
+			payload := map[string]any{
+				"dry_run": dryRun,
+			}
+
+			path := dagRunPath(args[0], args[1]) + "/clear"
+
+			if dryRun {
+				var response struct {
+					TaskInstances []taskInstanceState `document:"task_instances"`
+				}
+				if err := client.RestAPIPost(ctx, mwaaEnvName, path, nil, payload, &response); err != nil {
+					return err
+				}
+
+				cmd.Printf("The following %d task instances would be cleared:\n", len(response.TaskInstances))
+
+				for _, ti := range response.TaskInstances {
+					if ti.State == nil {
+						cmd.Printf("  - %s\n", ti.label())
+					} else {
+						cmd.Printf("  - %s (%s)\n", ti.label(), ti.state())
+					}
+				}
+
+				return nil
+			}
+
+			var response map[string]any
+			if err := client.RestAPIPost(ctx, mwaaEnvName, path, nil, payload, &response); err != nil {
+				return err
+			}
+
+			return printJSON(cmd, response)
+		},
+	}
+
+	cmd.Flags().BoolVar(&dryRun, "dry-run", false, "Only show the task instances that would be cleared")
+
+	cmd.Flags().StringVar(&mwaaEnvName, "env", "", "MWAA environment name")
+
+	return cmd
+}
+
+// newSetDagRunStateCommand creates the command to mark a DAG run as success or failed.
+func newSetDagRunStateCommand(globalOpts *globalOptions) *cobra.Command {
+	var mwaaEnvName string
+
+	cmd := &cobra.Command{
+		Use:           "set-state [dag-id] [run-id] success|failed",
+		Short:         "Mark a DAG run as success or failed",
+		SilenceUsage:  true,
+		SilenceErrors: true,
+		Args:          cobra.ExactArgs(3),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			state := args[2]
+			if state != "success" && state != "failed" {
+				return fmt.Errorf("invalid state: %s, expected success or failed", state)
+			}
+
+			ctx := context.Background()
+
+			client, err := initMWAAClient(ctx, globalOpts, &mwaaEnvName)
+			if err != nil {
+				return err
+			}
+
+			payload := map[string]any{
+				"state": state,
+			}
+
+			var response map[string]any
+			if err := client.RestAPIPatch(ctx, mwaaEnvName, dagRunPath(args[0], args[1]), nil, payload, &response); err != nil {
+				return err
+			}
+
+			return printJSON(cmd, response)
+		},
+	}
+
+	cmd.Flags().StringVar(&mwaaEnvName, "env", "", "MWAA environment name")
+
+	return cmd
+}
+
+// newDeleteDagRunCommand creates the command to delete a DAG run.
+func newDeleteDagRunCommand(globalOpts *globalOptions) *cobra.Command {
+	var mwaaEnvName string
+
+	cmd := &cobra.Command{
+		Use:           "delete [dag-id] [run-id]",
+		Short:         "Delete a DAG run",
+		SilenceUsage:  true,
+		SilenceErrors: true,
+		Args:          cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := context.Background()
+
+			client, err := initMWAAClient(ctx, globalOpts, &mwaaEnvName)
+			if err != nil {
+				return err
+			}
+
+			if err := client.RestAPIDelete(ctx, mwaaEnvName, dagRunPath(args[0], args[1]), nil); err != nil {
+				return err
+			}
+
+			cmd.Println(green("[SUCCESS]"), fmt.Sprintf("DAG run %s deleted.", args[1]))
+
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&mwaaEnvName, "env", "", "MWAA environment name")
+
+	return cmd
+}
+
+// dagRunPath returns the REST API path of a DAG run.
+func dagRunPath(dagID, dagRunID string) string {
+	return fmt.Sprintf("%s/dagRuns/%s", dagPath(dagID), url.PathEscape(dagRunID))
+}
+
+// taskInstanceState holds the fields of a task instance needed to track its progress.
+type taskInstanceState struct {
+	TaskID   string  `document:"task_id"`
+	MapIndex *int    `document:"map_index"`
+	State    *string `document:"state"`
+}
+
+// newTaskInstanceState extracts the progress fields from a task instance decoded as a map.
+func newTaskInstanceState(item map[string]any) taskInstanceState {
+	ti := taskInstanceState{
+		TaskID: fmt.Sprint(item["task_id"]),
+	}
+
+	if item["map_index"] != nil {
+		mapIndex := intField(item, "map_index")
+		ti.MapIndex = &mapIndex
+	}
+
+	if state, ok := item["state"].(string); ok {
+		ti.State = &state
+	}
+
+	return ti
+}
+
+// label returns the task ID, suffixed with the map index for mapped task instances.
+// Responses without a map index are treated as unmapped.
+func (ti taskInstanceState) label() string {
+	if ti.MapIndex != nil && *ti.MapIndex >= 0 {
+		return fmt.Sprintf("%s[%d]", ti.TaskID, *ti.MapIndex)
+	}
+
+	return ti.TaskID
+}
+
+// state returns the state of the task instance, or "none" if it has not been scheduled yet.
+func (ti taskInstanceState) state() string {
+	if ti.State == nil {
+		return "none"
+	}
+
+	return *ti.State
+}
@@ -0,0 +1,65 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDagRunPath(t *testing.T) {
+	assert.Equal(t, "/dags/example/dagRuns/manual__2024-01-01T00:00:00+00:00", dagRunPath("example", "manual__2024-01-01T00:00:00+00:00"))
+	assert.Equal(t, "/dags/example/dagRuns/a%2Fb", dagRunPath("example", "a/b"))
+	assert.Equal(t, "/dags/team%2Fexample/dagRuns/run", dagRunPath("team/example", "run"))
+}
+
+func TestNewTaskInstanceState(t *testing.T) {
+	ti := newTaskInstanceState(map[string]any{"task_id": "load", "map_index": float64(3), "state": "queued"})
+	assert.Equal(t, "load[3]", ti.label())
+	assert.Equal(t, "queued", ti.state())
+
+	ti = newTaskInstanceState(map[string]any{"task_id": "extract", "state": nil})
+	assert.Equal(t, "extract", ti.label())
+	assert.Equal(t, "none", ti.state())
+}
+
+func TestTaskInstanceState(t *testing.T) {
+	tests := []struct {
+		name          string
+		ti            taskInstanceState
+		expectedLabel string
+		expectedState string
+	}{
+		{
+			name:          "Unmapped task with state",
+			ti:            taskInstanceState{TaskID: "extract", MapIndex: aws.Int(-1), State: aws.String("running")},
+			expectedLabel: "extract",
+			expectedState: "running",
+		},
+		{
+			name:          "Mapped task without state",
+			ti:            taskInstanceState{TaskID: "load", MapIndex: aws.Int(2)},
+			expectedLabel: "load[2]",
+			expectedState: "none",
+		},
+		{
+			name:          "Task without map index",
+			ti:            taskInstanceState{TaskID: "transform", State: aws.String("success")},
+			expectedLabel: "transform",
+			expectedState: "success",
+		},
+		{
+			name:          "First mapped task",
+			ti:            taskInstanceState{TaskID: "load", MapIndex: aws.Int(0)},
+			expectedLabel: "load[0]",
+			expectedState: "none",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expectedLabel, tt.ti.label())
+			assert.Equal(t, tt.expectedState, tt.ti.state())
+		})
+	}
+}
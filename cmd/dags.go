@@ -2,10 +2,23 @@ package cmd
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
-
+	"io/fs"
+	"net/url"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/hupe1980/mwaacli/pkg/config"
 	"github.com/hupe1980/mwaacli/pkg/mwaa"
+	"github.com/hupe1980/mwaacli/pkg/s3"
+	"github.com/hupe1980/mwaacli/pkg/util"
 	"github.com/spf13/cobra"
 )
 
@@ -20,6 +33,11 @@ func newDagsCommand(globalOpts *globalOptions) *cobra.Command {
 	cmd.AddCommand(newListDagsCommand(globalOpts))
 	cmd.AddCommand(newGetDagCommand(globalOpts))
 	cmd.AddCommand(newGetDagSourceCommand(globalOpts))
+	cmd.AddCommand(newTriggerDagCommand(globalOpts))
+	cmd.AddCommand(newPauseDagsCommand(globalOpts, true))
+	cmd.AddCommand(newPauseDagsCommand(globalOpts, false))
+	cmd.AddCommand(newImportErrorsCommand(globalOpts))
+	cmd.AddCommand(newDeployDagsCommand(globalOpts))
 
 	return cmd
 }
@@ -27,16 +45,15 @@ func newDagsCommand(globalOpts *globalOptions) *cobra.Command {
 // newListDagsCommand creates the command to list DAGs in MWAA.
 func newListDagsCommand(globalOpts *globalOptions) *cobra.Command {
 	var (
-		limit        int
-		offset       int
-		orderBy      string
-		tags         []string
-		onlyActive   bool
-		paused       bool
-		unpaused     bool
-		fields       []string
-		dagIDPattern string
-		mwaaEnvName  string
+		limit       int
+		offset      int
+		all         bool
+		orderBy     string
+		filters     dagFilterOptions
+		paused      bool
+		unpaused    bool
+		fields      []string
+		mwaaEnvName string
 	)
 
 	cmd := &cobra.Command{
@@ -61,20 +78,12 @@ func newListDagsCommand(globalOpts *globalOptions) *cobra.Command {
 				}
 			}
 
-			queryParams := map[string]any{
-				"limit":       limit,
-				"offset":      offset,
-				"only_active": onlyActive,
-			}
+			queryParams := filters.queryParams()
 
 			if orderBy != "" {
 				queryParams["order_by"] = orderBy
 			}
 
-			if len(tags) > 0 {
-				queryParams["tags"] = tags
-			}
-
 			if len(fields) > 0 {
 				queryParams["fields"] = fields
 			}
@@ -87,36 +96,60 @@ func newListDagsCommand(globalOpts *globalOptions) *cobra.Command {
 				}
 			}
 
-			if dagIDPattern != "" {
-				queryParams["dag_id_pattern"] = dagIDPattern
-			}
-
-			var response struct {
-				Dags []map[string]any `json:"dags"`
-			}
-			if err := client.RestAPIGet(ctx, mwaaEnvName, "/dags", queryParams, &response); err != nil {
+			dags, err := fetchCollection(ctx, client, mwaaEnvName, "/dags", "dags", queryParams, limit, offset, all)
+			if err != nil {
 				return err
 			}
 
-			return printJSON(cmd, response.Dags)
+			return printJSON(cmd, dags)
 		},
 	}
 
 	cmd.Flags().IntVar(&limit, "limit", 100, "The number of items to return")
 	cmd.Flags().IntVar(&offset, "offset", 0, "The number of items to skip before starting to collect the result set")
+	cmd.Flags().BoolVar(&all, "all", false, "Fetch all pages of the result set, using --limit as page size")
 	cmd.Flags().StringVar(&orderBy, "order-by", "", "The name of the field to order the results by. Prefix a field name with - to reverse the sort order")
-	cmd.Flags().StringSliceVar(&tags, "tags", nil, "List of tags to filter results")
-	cmd.Flags().BoolVar(&onlyActive, "only-active", true, "Only filter active DAGs")
 	cmd.Flags().BoolVar(&paused, "paused", false, "Only filter paused DAGs")
 	cmd.Flags().BoolVar(&unpaused, "unpaused", false, "Only filter unpaused DAGs")
 	cmd.Flags().StringSliceVar(&fields, "fields", nil, "List of fields for return")
-	cmd.Flags().StringVar(&dagIDPattern, "dag-id-pattern", "", "If set, only return DAGs with dag_ids matching this pattern")
+	filters.addFlags(cmd)
 
 	cmd.Flags().StringVar(&mwaaEnvName, "env", "", "MWAA environment name")
 
 	return cmd
 }
 
+// dagFilterOptions holds the flags used to select a set of DAGs.
+type dagFilterOptions struct {
+	tags         []string
+	onlyActive   bool
+	dagIDPattern string
+}
+
+// addFlags registers the DAG filter flags on the given command.
+func (o *dagFilterOptions) addFlags(cmd *cobra.Command) {
+	cmd.Flags().StringSliceVar(&o.tags, "tags", nil, "List of tags to filter results")
+	cmd.Flags().BoolVar(&o.onlyActive, "only-active", true, "Only filter active DAGs")
+	cmd.Flags().StringVar(&o.dagIDPattern, "dag-id-pattern", "", "If set, only return DAGs with dag_ids matching this pattern")
+}
+
+// queryParams converts the DAG filters into REST API query parameters.
+func (o *dagFilterOptions) queryParams() map[string]any {
+	queryParams := map[string]any{
+		"only_active": o.onlyActive,
+	}
+
+	if len(o.tags) > 0 {
+		queryParams["tags"] = o.tags
+	}
+
+	if o.dagIDPattern != "" {
+		queryParams["dag_id_pattern"] = o.dagIDPattern
+	}
+
+	return queryParams
+}
+
 // newGetDagCommand creates the command to get details of a specific DAG.
 func newGetDagCommand(globalOpts *globalOptions) *cobra.Command {
 	var (
@@ -156,7 +189,7 @@ func newGetDagCommand(globalOpts *globalOptions) *cobra.Command {
 			}
 
 			var response map[string]any
-			if err := client.RestAPIGet(ctx, mwaaEnvName, fmt.Sprintf("/dags/%s", dagID), queryParams, &response); err != nil {
+			if err := client.RestAPIGet(ctx, mwaaEnvName, dagPath(dagID), queryParams, &response); err != nil {
 				return err
 			}
 
@@ -207,7 +240,7 @@ func newGetDagSourceCommand(globalOpts *globalOptions) *cobra.Command {
 			}
 
 			var response map[string]any
-			if err := client.RestAPIGet(ctx, mwaaEnvName, fmt.Sprintf("/dags/%s", dagID), queryParams, &response); err != nil {
+			if err := client.RestAPIGet(ctx, mwaaEnvName, dagPath(dagID), queryParams, &response); err != nil {
 				return err
 			}
 
@@ -226,3 +259,624 @@ func newGetDagSourceCommand(globalOpts *globalOptions) *cobra.Command {
 
 	return cmd
 }
+
+// newTriggerDagCommand creates the command to trigger a new DAG run.
+func newTriggerDagCommand(globalOpts *globalOptions) *cobra.Command {
+	var (
+		conf         string
+		logicalDate  string
+		runID        string
+		wait         bool
+		pollInterval time.Duration
+		timeout      time.Duration
+		mwaaEnvName  string
+	)
+
+	cmd := &cobra.Command{
+		Use:           "trigger [dag-id]",
+		Short:         "Trigger a new DAG run",
+		SilenceUsage:  true,
+		SilenceErrors: true,
+		Args:          cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			dagID := args[0]
+
+			payload := map[string]any{}
+
+			if conf != "" {
+				parsedConf, err := parseJSONObject(conf)
+				if err != nil {
+					return fmt.Errorf("invalid conf: %w", err)
+				}
+
+				payload["conf"] = parsedConf
+			}
+
+			if logicalDate != "" {
+				date, err := time.Parse(time.RFC3339, logicalDate)
+				if err != nil {
+					return fmt.Errorf("invalid logical date format: %w", err)
+				}
+
+				payload["logical_date"] = date.Format(time.RFC3339)
+			}
+
+			if runID != "" {
+				payload["dag_run_id"] = runID
+			}
+
+			ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+			defer stop()
+
+			client, err := initMWAAClient(ctx, globalOpts, &mwaaEnvName)
+			if err != nil {
+				return err
+			}
+
+			var response map[string]any
+			if err := client.RestAPIPost(ctx, mwaaEnvName, dagPath(dagID)+"/dagRuns", nil, payload, &response); err != nil {
+				return err
+			}
+
+			if err := printJSON(cmd, response); err != nil {
+				return err
+			}
+
+			if !wait {
+				return nil
+			}
+
+			dagRunID, _ := response["dag_run_id"].(string)
+
+			if timeout > 0 {
+				var cancel context.CancelFunc
+
+				ctx, cancel = context.WithTimeout(ctx, timeout)
+				defer cancel()
+			}
+
+			state, err := waitForDagRun(ctx, cmd, client, mwaaEnvName, dagID, dagRunID, pollInterval)
+			if err != nil {
+				if errors.Is(err, context.DeadlineExceeded) {
+					return fmt.Errorf("timed out after %s waiting for DAG run %s", timeout, dagRunID)
+				}
+
+				return err
+			}
+
+			if state != "success" {
+				return fmt.Errorf("DAG run %s finished with state %s", dagRunID, state)
+			}
+
+			cmd.PrintErrln(green("[SUCCESS]"), fmt.Sprintf("DAG run %s finished successfully.", dagRunID))
+
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&conf, "conf", "", "JSON configuration for the DAG run, given inline or as a path to a JSON file")
+	cmd.Flags().StringVar(&logicalDate, "logical-date", "", "Logical date of the DAG run in RFC3339 format (default: now)")
+	cmd.Flags().StringVar(&runID, "run-id", "", "ID of the DAG run (default: generated by Airflow)")
+	cmd.Flags().BoolVar(&wait, "wait", false, "Wait until the DAG run reaches a terminal state")
+	cmd.Flags().DurationVar(&pollInterval, "poll-interval", 10*time.Second, "Interval between status checks when waiting")
+	cmd.Flags().DurationVar(&timeout, "timeout", 0, "Maximum time to wait for the DAG run (0 waits indefinitely)")
+
+	cmd.Flags().StringVar(&mwaaEnvName, "env", "", "MWAA environment name")
+
+	return cmd
+}
+
+// waitForDagRun polls a DAG run until it reaches a terminal state and returns that state.
+// Task instance state changes are printed as they are observed.
+func waitForDagRun(ctx context.Context, cmd *cobra.Command, client *mwaa.Client, mwaaEnvName, dagID, dagRunID string, pollInterval time.Duration) (string, error) {
+	runPath := dagRunPath(dagID, dagRunID)
+	taskStates := map[string]string{}
+
+	for {
+		var dagRun struct {
+			State string `document:"state"`
+		}
+		if err := client.RestAPIGet(ctx, mwaaEnvName, runPath, nil, &dagRun); err != nil {
+			return "", err
+		}
+
+		items, err := fetchCollection(ctx, client, mwaaEnvName, runPath+"/taskInstances", "task_instances", nil, 100, 0, true)
+		if err != nil {
+			return "", err
+		}
+
+		for _, item := range items {
+			ti := newTaskInstanceState(item)
+
+			if label, state := ti.label(), ti.state(); taskStates[label] != state {
+				taskStates[label] = state
+				cmd.PrintErrln(cyan("[INFO]"), fmt.Sprintf("%s: %s", label, state))
+			}
+		}
+
+		if dagRun.State == "success" || dagRun.State == "failed" {
+			return dagRun.State, nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return "", ctx.Err()
+		case <-time.After(pollInterval):
+		}
+	}
+}
+
+// dagPath returns the REST API path of a DAG.
+func dagPath(dagID string) string {
+	return fmt.Sprintf("/dags/%s", url.PathEscape(dagID))
+}
+
+// parseJSONObject parses a JSON object given either inline or as a path to a JSON file.
+func parseJSONObject(value string) (map[string]any, error) {
+	data := []byte(value)
+
+	if !strings.HasPrefix(strings.TrimSpace(value), "{") {
+		content, err := os.ReadFile(value)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read file %s: %w", value, err)
+		}
+
+		data = content
+	}
+
+	var result map[string]any
+	if err := json.Unmarshal(data, &result); err != nil {
+		return nil, fmt.Errorf("failed to parse JSON: %w", err)
+	}
+
+	return result, nil
+}
+
+// newPauseDagsCommand creates the command to pause or unpause one or more DAGs.
+func newPauseDagsCommand(globalOpts *globalOptions, isPaused bool) *cobra.Command {
+	var (
+		limit       int
+		filters     dagFilterOptions
+		yes         bool
+		mwaaEnvName string
+	)
+
+	use, short, action := "unpause", "Unpause a DAG or all DAGs matching a selector", "unpaused"
+	if isPaused {
+		use, short, action = "pause", "Pause a DAG or all DAGs matching a selector", "paused"
+	}
+
+	cmd := &cobra.Command{
+		Use:           fmt.Sprintf("%s [dag-id]", use),
+		Short:         short,
+		SilenceUsage:  true,
+		SilenceErrors: true,
+		Args:          cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			hasSelector := filters.dagIDPattern != "" || len(filters.tags) > 0
+
+			if len(args) > 0 && hasSelector {
+				return fmt.Errorf("a dag-id cannot be combined with --dag-id-pattern or --tags")
+			}
+
+			if len(args) == 0 && !hasSelector {
+				return fmt.Errorf("either a dag-id or --dag-id-pattern/--tags is required")
+			}
+
+			ctx := context.Background()
+
+			client, err := initMWAAClient(ctx, globalOpts, &mwaaEnvName)
+			if err != nil {
+				return err
+			}
+
+			payload := map[string]any{
+				"is_paused": isPaused,
+			}
+
+			if len(args) > 0 {
+				queryParams := map[string]any{
+					"update_mask": "is_paused",
+				}
+
+				var response map[string]any
+				if err := client.RestAPIPatch(ctx, mwaaEnvName, dagPath(args[0]), queryParams, payload, &response); err != nil {
+					return err
+				}
+
+				return printJSON(cmd, response)
+			}
+
+			queryParams := filters.queryParams()
+
+			matched, err := fetchCollection(ctx, client, mwaaEnvName, "/dags", "dags", queryParams, limit, 0, true)
+			if err != nil {
+				return err
+			}
+
+			if len(matched) == 0 {
+				cmd.Println(cyan("[INFO]"), "No DAGs matched the given selector.")
+				return nil
+			}
+
+			cmd.Printf("The following %d DAGs will be %s:\n", len(matched), action)
+
+			for _, dag := range matched {
+				if dagIsPaused, _ := dag["is_paused"].(bool); dagIsPaused == isPaused {
+					cmd.Printf("  - %s (already %s)\n", dag["dag_id"], action)
+				} else {
+					cmd.Printf("  - %s\n", dag["dag_id"])
+				}
+			}
+
+			if !yes {
+				ok, err := confirm("Do you want to continue")
+				if err != nil {
+					return err
+				}
+
+				if !ok {
+					cmd.Println(cyan("[INFO]"), "Aborted.")
+					return nil
+				}
+			}
+
+			// The bulk endpoint requires a pattern; "~" matches all DAG IDs.
+			if filters.dagIDPattern == "" {
+				queryParams["dag_id_pattern"] = "~"
+			}
+
+			queryParams["update_mask"] = "is_paused"
+			queryParams["limit"] = limit
+
+			// The bulk endpoint only updates a single page, so the matched DAGs are patched page by page.
+			updated := 0
+
+			for updated < len(matched) {
+				queryParams["offset"] = updated
+
+				var response struct {
+					Dags []map[string]any `document:"dags"`
+				}
+				if err := client.RestAPIPatch(ctx, mwaaEnvName, "/dags", queryParams, payload, &response); err != nil {
+					return fmt.Errorf("failed after %d of %d DAGs were %s: %w", updated, len(matched), action, err)
+				}
+
+				if len(response.Dags) == 0 {
+					break
+				}
+
+				updated += len(response.Dags)
+			}
+
+			if updated < len(matched) {
+				return fmt.Errorf("only %d of %d DAGs were %s", updated, len(matched), action)
+			}
+
+			cmd.Println(green("[SUCCESS]"), fmt.Sprintf("%d DAGs %s.", updated, action))
+
+			return nil
+		},
+	}
+
+	cmd.Flags().IntVar(&limit, "limit", 100, "The number of DAGs to select and update per request")
+	filters.addFlags(cmd)
+	cmd.Flags().BoolVarP(&yes, "yes", "y", false, "Skip the confirmation prompt")
+
+	cmd.Flags().StringVar(&mwaaEnvName, "env", "", "MWAA environment name")
+
+	return cmd
+}
+
+// newImportErrorsCommand creates the command to report DAG import errors.
+func newImportErrorsCommand(globalOpts *globalOptions) *cobra.Command {
+	var (
+		lines        int
+		failOnErrors bool
+		mwaaEnvName  string
+	)
+
+	cmd := &cobra.Command{
+		Use:           "import-errors",
+		Short:         "Report DAG files that failed to import, grouped by file",
+		SilenceUsage:  true,
+		SilenceErrors: true,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			ctx := context.Background()
+
+			client, err := initMWAAClient(ctx, globalOpts, &mwaaEnvName)
+			if err != nil {
+				return err
+			}
+
+			items, err := fetchCollection(ctx, client, mwaaEnvName, "/importErrors", "import_errors", nil, 100, 0, true)
+			if err != nil {
+				return err
+			}
+
+			if len(items) == 0 {
+				cmd.Println(green("[SUCCESS]"), "No import errors found.")
+				return nil
+			}
+
+			groups := groupImportErrors(items)
+
+			for _, group := range groups {
+				cmd.Printf("%s (%d errors)\n", red(group.filename), len(group.errors))
+
+				for _, importErr := range group.errors {
+					cmd.Printf("  [%s]\n", importErr.timestamp)
+
+					for _, line := range truncateStackTrace(importErr.stackTrace, lines) {
+						cmd.Printf("    %s\n", line)
+					}
+				}
+
+				cmd.Println()
+			}
+
+			if failOnErrors {
+				return fmt.Errorf("found %d import errors in %d files", len(items), len(groups))
+			}
+
+			return nil
+		},
+	}
+
+	cmd.Flags().IntVar(&lines, "lines", 5, "Number of leading stack trace lines to print per error besides the exception line (0 prints the full stack trace)")
+	cmd.Flags().BoolVar(&failOnErrors, "fail-on-errors", false, "Exit with a non-zero code if any import errors are found")
+
+	cmd.Flags().StringVar(&mwaaEnvName, "env", "", "MWAA environment name")
+
+	return cmd
+}
+
+// importError is a single DAG import error.
+type importError struct {
+	timestamp  string
+	stackTrace string
+}
+
+// importErrorGroup holds the import errors of a single DAG file.
+type importErrorGroup struct {
+	filename string
+	errors   []importError
+}
+
+// groupImportErrors groups import errors by file name, sorted by file name.
+func groupImportErrors(items []map[string]any) []importErrorGroup {
+	byFile := map[string][]importError{}
+
+	for _, item := range items {
+		filename := fmt.Sprint(item["filename"])
+		timestamp, _ := item["timestamp"].(string)
+		stackTrace, _ := item["stack_trace"].(string)
+
+		byFile[filename] = append(byFile[filename], importError{
+			timestamp:  timestamp,
+			stackTrace: stackTrace,
+		})
+	}
+
+	groups := make([]importErrorGroup, 0, len(byFile))
+	for filename, errs := range byFile {
+		groups = append(groups, importErrorGroup{filename: filename, errors: errs})
+	}
+
+	sort.Slice(groups, func(i, j int) bool {
+		return groups[i].filename < groups[j].filename
+	})
+
+	return groups
+}
+
+// truncateStackTrace returns the first n non-empty lines of a stack trace followed by its last non-empty line,
+// which names the exception. All lines are returned if n is not positive.
+func truncateStackTrace(s string, n int) []string {
+	var lines []string
+
+	for _, line := range strings.Split(strings.TrimSpace(s), "\n") {
+		if strings.TrimSpace(line) != "" {
+			lines = append(lines, line)
+		}
+	}
+
+	if n <= 0 || len(lines) <= n+1 {
+		return lines
+	}
+
+	return append(lines[:n:n], "...", lines[len(lines)-1])
+}
+
+// newDeployDagsCommand creates the command to deploy a local DAG folder to the environment's S3 bucket.
+func newDeployDagsCommand(globalOpts *globalOptions) *cobra.Command {
+	var (
+		deleteRemote    bool
+		dryRun          bool
+		include         []string
+		exclude         []string
+		noAirflowIgnore bool
+		yes             bool
+		mwaaEnvName     string
+	)
+
+	cmd := &cobra.Command{
+		Use:   "deploy [local-dir]",
+		Short: "Upload changed DAG files to the environment's S3 bucket",
+		Long: `Upload the DAG files of a local directory that are new or changed to the DAG folder
+of the environment's S3 bucket. Files are compared by their MD5 checksum and S3 ETag.
+Paths listed in .airflowignore files are skipped unless --no-airflowignore is set. Modules that DAGs
+import but Airflow should not parse are often listed there, so check the skipped files in the plan.`,
+		SilenceUsage:  true,
+		SilenceErrors: true,
+		Args:          cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			localDir := args[0]
+
+			if info, err := os.Stat(localDir); err != nil || !info.IsDir() {
+				return fmt.Errorf("local directory %s does not exist", localDir)
+			}
+
+			filter, err := util.NewPathFilter(localDir, include, exclude, !noAirflowIgnore)
+			if err != nil {
+				return err
+			}
+
+			ignored, err := airflowIgnoredFiles(localDir, filter)
+			if err != nil {
+				return err
+			}
+
+			ctx := context.Background()
+
+			cfg, client, err := initMWAAClientWithConfig(ctx, globalOpts, &mwaaEnvName)
+			if err != nil {
+				return err
+			}
+
+			environment, err := client.GetEnvironment(ctx, mwaaEnvName)
+			if err != nil {
+				return err
+			}
+
+			bucket, err := s3.BucketNameFromARN(aws.ToString(environment.SourceBucketArn))
+			if err != nil {
+				return err
+			}
+
+			if environment.DagS3Path == nil {
+				return fmt.Errorf("environment %s has no DAG folder configured", mwaaEnvName)
+			}
+
+			s3Client := s3.NewClient(cfg)
+
+			plan, err := s3Client.PlanUploadDirectory(ctx, &s3.PlanUploadDirectoryInput{
+				Bucket:   aws.String(bucket),
+				Prefix:   environment.DagS3Path,
+				LocalDir: aws.String(localDir),
+				Filter:   filter.Match,
+				Delete:   deleteRemote,
+			})
+			if err != nil {
+				return err
+			}
+
+			if len(plan.Operations) == 0 {
+				if dryRun {
+					printIgnoredFiles(cmd, ignored)
+				}
+
+				cmd.Println(green("[SUCCESS]"), fmt.Sprintf("DAGs are up to date (%d files).", plan.Unchanged))
+
+				return nil
+			}
+
+			printUploadPlan(cmd, plan)
+			printIgnoredFiles(cmd, ignored)
+
+			if dryRun {
+				return nil
+			}
+
+			if !yes {
+				ok, err := confirm("Do you want to continue")
+				if err != nil {
+					return err
+				}
+
+				if !ok {
+					cmd.Println(cyan("[INFO]"), "Aborted.")
+					return nil
+				}
+			}
+
+			if err := s3Client.ApplyUploadPlan(ctx, plan, func(op s3.SyncOperation) {
+				cmd.Printf("%s s3://%s/%s\n", op.Action, plan.Bucket, op.Key)
+			}); err != nil {
+				return err
+			}
+
+			cmd.Println(green("[SUCCESS]"), fmt.Sprintf("DAGs deployed to s3://%s/%s.", plan.Bucket, aws.ToString(environment.DagS3Path)))
+
+			return nil
+		},
+	}
+
+	cmd.Flags().BoolVar(&deleteRemote, "delete", false, "Delete remote files that do not exist in the local directory")
+	cmd.Flags().BoolVar(&dryRun, "dry-run", false, "Print the plan without uploading or deleting files")
+	cmd.Flags().StringSliceVar(&include, "include", nil, "Only deploy files matching these glob patterns")
+	cmd.Flags().StringSliceVar(&exclude, "exclude", []string{"__pycache__", "*.pyc"}, "Skip files and directories matching these glob patterns")
+	cmd.Flags().BoolVar(&noAirflowIgnore, "no-airflowignore", false, "Also deploy files listed in .airflowignore files")
+	cmd.Flags().BoolVarP(&yes, "yes", "y", false, "Skip the confirmation prompt")
+
+	cmd.Flags().StringVar(&mwaaEnvName, "env", "", "MWAA environment name")
+
+	return cmd
+}
+
+// printUploadPlan prints the operations of an upload plan followed by a summary.
+func printUploadPlan(cmd *cobra.Command, plan *s3.UploadPlan) {
+	uploads, deletes := 0, 0
+
+	for _, op := range plan.Operations {
+		switch op.Action {
+		case s3.SyncActionUpload:
+			uploads++
+
+			if op.Reason == "new" {
+				cmd.Printf("  %s %s\n", green("+"), op.Key)
+			} else {
+				cmd.Printf("  %s %s\n", yellow("~"), op.Key)
+			}
+		case s3.SyncActionDelete:
+			deletes++
+
+			cmd.Printf("  %s %s\n", red("-"), op.Key)
+		}
+	}
+
+	cmd.Printf("\nPlan: %d to upload, %d to delete, %d unchanged.\n", uploads, deletes, plan.Unchanged)
+}
+
+// airflowIgnoredFiles returns the slash-separated relative paths of the files in localDir that the filter
+// only rejects because of an .airflowignore file, sorted by path.
+func airflowIgnoredFiles(localDir string, filter *util.PathFilter) ([]string, error) {
+	var ignored []string
+
+	err := filepath.WalkDir(localDir, func(p string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+
+		relPath, err := filepath.Rel(localDir, p)
+		if err != nil {
+			return err
+		}
+
+		if relPath = filepath.ToSlash(relPath); filter.Ignored(relPath) {
+			ignored = append(ignored, relPath)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to read local directory %s: %w", localDir, err)
+	}
+
+	return ignored, nil
+}
+
+// printIgnoredFiles prints the local files that are skipped because of an .airflowignore file.
+func printIgnoredFiles(cmd *cobra.Command, ignored []string) {
+	if len(ignored) == 0 {
+		return
+	}
+
+	cmd.Printf("\nSkipped by %s (deploy them with --no-airflowignore):\n", util.AirflowIgnoreFile)
+
+	for _, relPath := range ignored {
+		cmd.Printf("  %s %s\n", cyan("!"), relPath)
+	}
+
+	cmd.Println()
+}
@@ -0,0 +1,178 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/hupe1980/mwaacli/pkg/util"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseJSONObject(t *testing.T) {
+	tempFile, err := os.CreateTemp("", "conf-*.json")
+	assert.NoError(t, err)
+	defer os.Remove(tempFile.Name())
+
+	_, err = tempFile.WriteString(`{"source": "file"}`)
+	assert.NoError(t, err)
+	assert.NoError(t, tempFile.Close())
+
+	tests := []struct {
+		name        string
+		value       string
+		expected    map[string]any
+		expectError bool
+	}{
+		{
+			name:     "Inline JSON object",
+			value:    `{"key": "value", "count": 1}`,
+			expected: map[string]any{"key": "value", "count": float64(1)},
+		},
+		{
+			name:     "JSON file",
+			value:    tempFile.Name(),
+			expected: map[string]any{"source": "file"},
+		},
+		{
+			name:        "Invalid inline JSON",
+			value:       `{"key":`,
+			expectError: true,
+		},
+		{
+			name:        "Missing file",
+			value:       "nonexistent.json",
+			expectError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := parseJSONObject(tt.value)
+
+			if tt.expectError {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.expected, result)
+			}
+		})
+	}
+}
+
+func TestDagFilterOptionsQueryParams(t *testing.T) {
+	tests := []struct {
+		name     string
+		opts     dagFilterOptions
+		expected map[string]any
+	}{
+		{
+			name:     "Only active",
+			opts:     dagFilterOptions{onlyActive: true},
+			expected: map[string]any{"only_active": true},
+		},
+		{
+			name: "Tags and pattern",
+			opts: dagFilterOptions{
+				tags:         []string{"team-a", "daily"},
+				dagIDPattern: "etl_",
+			},
+			expected: map[string]any{
+				"only_active":    false,
+				"tags":           []string{"team-a", "daily"},
+				"dag_id_pattern": "etl_",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, tt.opts.queryParams())
+		})
+	}
+}
+
+func TestGroupImportErrors(t *testing.T) {
+	items := []map[string]any{
+		{"filename": "dags/b.py", "timestamp": "2024-01-01T00:00:00+00:00", "stack_trace": "Traceback b1"},
+		{"filename": "dags/a.py", "timestamp": "2024-01-01T00:00:00+00:00", "stack_trace": "Traceback a1"},
+		{"filename": "dags/b.py", "timestamp": "2024-01-02T00:00:00+00:00", "stack_trace": "Traceback b2"},
+	}
+
+	groups := groupImportErrors(items)
+
+	assert.Len(t, groups, 2)
+	assert.Equal(t, "dags/a.py", groups[0].filename)
+	assert.Len(t, groups[0].errors, 1)
+	assert.Equal(t, "dags/b.py", groups[1].filename)
+	assert.Equal(t, []importError{
+		{timestamp: "2024-01-01T00:00:00+00:00", stackTrace: "Traceback b1"},
+		{timestamp: "2024-01-02T00:00:00+00:00", stackTrace: "Traceback b2"},
+	}, groups[1].errors)
+}
+
+func TestTruncateStackTrace(t *testing.T) {
+	stackTrace := "Traceback (most recent call last):\n\n  File \"a.py\", line 1\n    import foo\nModuleNotFoundError: No module named 'foo'\n"
+
+	tests := []struct {
+		name     string
+		n        int
+		expected []string
+	}{
+		{
+			name:     "Truncated",
+			n:        2,
+			expected: []string{"Traceback (most recent call last):", "  File \"a.py\", line 1", "...", "ModuleNotFoundError: No module named 'foo'"},
+		},
+		{
+			name: "Only the exception line is left",
+			n:    3,
+			expected: []string{
+				"Traceback (most recent call last):",
+				"  File \"a.py\", line 1",
+				"    import foo",
+				"ModuleNotFoundError: No module named 'foo'",
+			},
+		},
+		{
+			name: "Full stack trace",
+			n:    0,
+			expected: []string{
+				"Traceback (most recent call last):",
+				"  File \"a.py\", line 1",
+				"    import foo",
+				"ModuleNotFoundError: No module named 'foo'",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, truncateStackTrace(stackTrace, tt.n))
+		})
+	}
+}
+
+func TestAirflowIgnoredFiles(t *testing.T) {
+	localDir := t.TempDir()
+
+	assert.NoError(t, os.MkdirAll(filepath.Join(localDir, "common", "__pycache__"), 0755))
+	assert.NoError(t, os.WriteFile(filepath.Join(localDir, util.AirflowIgnoreFile), []byte("common/\n"), 0600))
+	assert.NoError(t, os.WriteFile(filepath.Join(localDir, "dag.py"), []byte("import common.helpers\n"), 0600))
+	assert.NoError(t, os.WriteFile(filepath.Join(localDir, "common", "helpers.py"), []byte(""), 0600))
+	assert.NoError(t, os.WriteFile(filepath.Join(localDir, "common", "__pycache__", "helpers.cpython-311.pyc"), []byte(""), 0600))
+
+	filter, err := util.NewPathFilter(localDir, nil, []string{"__pycache__", "*.pyc"}, true)
+	assert.NoError(t, err)
+
+	ignored, err := airflowIgnoredFiles(localDir, filter)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"common/helpers.py"}, ignored)
+
+	filter, err = util.NewPathFilter(localDir, nil, []string{"__pycache__", "*.pyc"}, false)
+	assert.NoError(t, err)
+
+	ignored, err = airflowIgnoredFiles(localDir, filter)
+	assert.NoError(t, err)
+	assert.Empty(t, ignored)
+}
@@ -1,11 +1,26 @@
 package cmd
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/signal"
+	"slices"
+	"sort"
+	"strings"
+	"time"
 
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/mwaa/types"
+	"github.com/briandowns/spinner"
 	"github.com/hupe1980/mwaacli/pkg/config"
 	"github.com/hupe1980/mwaacli/pkg/mwaa"
+	"github.com/hupe1980/mwaacli/pkg/s3"
 	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
 )
 
 // newEnvironmentCommand creates a new cobra command for managing MWAA environments.
@@ -18,6 +33,18 @@ func newEnvironmentsCommand(globalOpts *globalOptions) *cobra.Command {
 
 	cmd.AddCommand(newListEnvironmentsCommand(globalOpts))
 	cmd.AddCommand(newGetEnvironmentCommand(globalOpts))
+	cmd.AddCommand(newEnvironmentHealthCommand(globalOpts))
+	cmd.AddCommand(newCreateEnvironmentCommand(globalOpts))
+	cmd.AddCommand(newUpdateEnvironmentCommand(globalOpts))
+	cmd.AddCommand(newDeleteEnvironmentCommand(globalOpts))
+	cmd.AddCommand(newWaitEnvironmentCommand(globalOpts))
+	cmd.AddCommand(newPlanEnvironmentCommand(globalOpts))
+	cmd.AddCommand(newExportEnvironmentCommand(globalOpts))
+	cmd.AddCommand(newCompareEnvironmentsCommand(globalOpts))
+	cmd.AddCommand(newPublishArtifactCommand(globalOpts, mwaa.ArtifactRequirements, "requirements.txt"))
+	cmd.AddCommand(newPublishArtifactCommand(globalOpts, mwaa.ArtifactPlugins, "plugins.zip"))
+	cmd.AddCommand(newArtifactVersionsCommand(globalOpts))
+	cmd.AddCommand(newRollbackArtifactCommand(globalOpts))
 
 	return cmd
 }
@@ -89,3 +116,1141 @@ func newGetEnvironmentCommand(globalOpts *globalOptions) *cobra.Command {
 
 	return cmd
 }
+
+// newEnvironmentHealthCommand creates a cobra command to check the health of an MWAA environment.
+func newEnvironmentHealthCommand(globalOpts *globalOptions) *cobra.Command {
+	var output string
+
+	cmd := &cobra.Command{
+		Use:   "health [environment]",
+		Short: "Check the health of an MWAA environment",
+		Long: `Check the health of an MWAA environment by combining the environment status and last update,
+the Airflow health endpoint, the number of DAG import errors and the enabled log groups into one report.
+Exits with a non-zero code if any check is unhealthy.`,
+		SilenceUsage:  true,
+		SilenceErrors: true,
+		Args:          cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if output != "json" && output != "table" {
+				return fmt.Errorf("invalid output format: %s, expected json or table", output)
+			}
+
+			ctx := context.Background()
+
+			var mwaaEnvName string
+			if len(args) > 0 {
+				mwaaEnvName = args[0]
+			}
+
+			client, err := initMWAAClient(ctx, globalOpts, &mwaaEnvName)
+			if err != nil {
+				return err
+			}
+
+			environment, err := client.GetEnvironment(ctx, mwaaEnvName)
+			if err != nil {
+				return err
+			}
+
+			checks := environmentHealthChecks(environment)
+
+			var health map[string]any
+			if err := client.RestAPIGet(ctx, mwaaEnvName, "/health", nil, &health); err != nil {
+				checks = append(checks, healthCheck{Name: "airflow", Detail: fmt.Sprintf("failed to query /health: %s", err)})
+			} else {
+				checks = append(checks, airflowHealthChecks(health)...)
+			}
+
+			var importErrors map[string]any
+			if err := client.RestAPIGet(ctx, mwaaEnvName, "/importErrors", map[string]any{"limit": 1}, &importErrors); err != nil {
+				checks = append(checks, healthCheck{Name: "import-errors", Detail: fmt.Sprintf("failed to query /importErrors: %s", err)})
+			} else {
+				checks = append(checks, importErrorsHealthCheck(intField(importErrors, "total_entries")))
+			}
+
+			checks = append(checks, loggingHealthChecks(environment.LoggingConfiguration)...)
+
+			if output == "json" {
+				if err := printJSON(cmd, checks); err != nil {
+					return err
+				}
+			} else {
+				rows := make([][]string, 0, len(checks))
+				for _, check := range checks {
+					status := green("OK")
+					if !check.Healthy {
+						status = red("FAIL")
+					}
+
+					rows = append(rows, []string{check.Name, status, check.Detail})
+				}
+
+				if err := printTable(cmd, []string{"CHECK", "STATUS", "DETAIL"}, rows); err != nil {
+					return err
+				}
+			}
+
+			if failed := unhealthyChecks(checks); failed > 0 {
+				return fmt.Errorf("environment %s is unhealthy: %d of %d checks failed", mwaaEnvName, failed, len(checks))
+			}
+
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVarP(&output, "output", "o", "table", "Output format (json or table)")
+
+	return cmd
+}
+
+// healthCheck is the result of a single environment health check.
+type healthCheck struct {
+	Name    string `json:"name"`
+	Healthy bool   `json:"healthy"`
+	Detail  string `json:"detail"`
+}
+
+// unhealthyChecks returns the number of checks that are not healthy.
+func unhealthyChecks(checks []healthCheck) int {
+	failed := 0
+
+	for _, check := range checks {
+		if !check.Healthy {
+			failed++
+		}
+	}
+
+	return failed
+}
+
+// environmentHealthChecks checks the status and the last update of an MWAA environment.
+func environmentHealthChecks(environment *types.Environment) []healthCheck {
+	checks := []healthCheck{{
+		Name:    "environment-status",
+		Healthy: environment.Status == types.EnvironmentStatusAvailable,
+		Detail:  string(environment.Status),
+	}}
+
+	lastUpdate := healthCheck{Name: "last-update", Healthy: true, Detail: "none"}
+
+	if update := environment.LastUpdate; update != nil {
+		lastUpdate.Detail = string(update.Status)
+
+		if update.CreatedAt != nil {
+			lastUpdate.Detail = fmt.Sprintf("%s at %s", update.Status, update.CreatedAt.Format(time.RFC3339))
+		}
+
+		if update.Error != nil {
+			lastUpdate.Healthy = false
+			lastUpdate.Detail = fmt.Sprintf("%s: %s", aws.ToString(update.Error.ErrorCode), aws.ToString(update.Error.ErrorMessage))
+		}
+	}
+
+	return append(checks, lastUpdate)
+}
+
+// airflowHealthChecks checks the metadatabase and scheduler status reported by the Airflow /health endpoint.
+func airflowHealthChecks(health map[string]any) []healthCheck {
+	component := func(name string) (map[string]any, string) {
+		info, _ := health[name].(map[string]any)
+		status, _ := info["status"].(string)
+
+		return info, status
+	}
+
+	_, databaseStatus := component("metadatabase")
+
+	checks := []healthCheck{{
+		Name:    "metadatabase",
+		Healthy: databaseStatus == "healthy",
+		Detail:  databaseStatus,
+	}}
+
+	scheduler, schedulerStatus := component("scheduler")
+
+	detail := schedulerStatus
+	if heartbeat, ok := scheduler["latest_scheduler_heartbeat"].(string); ok && heartbeat != "" {
+		detail = fmt.Sprintf("%s, last heartbeat %s", schedulerStatus, heartbeat)
+	}
+
+	return append(checks, healthCheck{
+		Name:    "scheduler",
+		Healthy: schedulerStatus == "healthy",
+		Detail:  detail,
+	})
+}
+
+// importErrorsHealthCheck checks that there are no DAG import errors.
+func importErrorsHealthCheck(count int) healthCheck {
+	return healthCheck{
+		Name:    "import-errors",
+		Healthy: count == 0,
+		Detail:  fmt.Sprintf("%d import errors", count),
+	}
+}
+
+// loggingHealthChecks checks that every log group of the logging configuration is enabled.
+func loggingHealthChecks(loggingConfig *types.LoggingConfiguration) []healthCheck {
+	if loggingConfig == nil {
+		loggingConfig = &types.LoggingConfiguration{}
+	}
+
+	modules := []struct {
+		logType string
+		config  *types.ModuleLoggingConfiguration
+	}{
+		{"dag-processing", loggingConfig.DagProcessingLogs},
+		{"scheduler", loggingConfig.SchedulerLogs},
+		{"task", loggingConfig.TaskLogs},
+		{"webserver", loggingConfig.WebserverLogs},
+		{"worker", loggingConfig.WorkerLogs},
+	}
+
+	checks := make([]healthCheck, 0, len(modules))
+
+	for _, module := range modules {
+		check := healthCheck{Name: fmt.Sprintf("%s-logs", module.logType), Detail: "disabled"}
+
+		if module.config != nil && aws.ToBool(module.config.Enabled) {
+			check.Healthy = true
+			check.Detail = fmt.Sprintf("enabled (%s)", module.config.LogLevel)
+		}
+
+		checks = append(checks, check)
+	}
+
+	return checks
+}
+
+// newCreateEnvironmentCommand creates a cobra command to create an MWAA environment from a spec file.
+func newCreateEnvironmentCommand(globalOpts *globalOptions) *cobra.Command {
+	var specFile string
+
+	cmd := &cobra.Command{
+		Use:           "create",
+		Short:         "Create an MWAA environment from a YAML or JSON spec file",
+		SilenceUsage:  true,
+		SilenceErrors: true,
+		Args:          cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			spec, err := mwaa.LoadEnvironmentSpec(specFile)
+			if err != nil {
+				return err
+			}
+
+			input, err := spec.CreateEnvironmentInput()
+			if err != nil {
+				return err
+			}
+
+			cfg, err := config.NewConfig(globalOpts.profile, globalOpts.region)
+			if err != nil {
+				return err
+			}
+
+			client := mwaa.NewClient(cfg)
+
+			arn, err := client.CreateEnvironment(context.Background(), input)
+			if err != nil {
+				return err
+			}
+
+			cmd.Println(green("[SUCCESS]"), fmt.Sprintf("Creation of environment %s started (%s).", spec.Name, arn))
+
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVarP(&specFile, "file", "f", "", "Path to the environment spec file, ${NAME} placeholders are expanded from environment variables (required)")
+
+	_ = cmd.MarkFlagRequired("file")
+
+	return cmd
+}
+
+// newUpdateEnvironmentCommand creates a cobra command to update an MWAA environment from a spec file.
+func newUpdateEnvironmentCommand(globalOpts *globalOptions) *cobra.Command {
+	var specFile string
+
+	cmd := &cobra.Command{
+		Use:           "update",
+		Short:         "Update an MWAA environment from a YAML or JSON spec file",
+		SilenceUsage:  true,
+		SilenceErrors: true,
+		Args:          cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			spec, err := mwaa.LoadEnvironmentSpec(specFile)
+			if err != nil {
+				return err
+			}
+
+			cfg, err := config.NewConfig(globalOpts.profile, globalOpts.region)
+			if err != nil {
+				return err
+			}
+
+			client := mwaa.NewClient(cfg)
+
+			arn, err := client.UpdateEnvironment(context.Background(), spec.UpdateEnvironmentInput())
+			if err != nil {
+				return err
+			}
+
+			cmd.Println(green("[SUCCESS]"), fmt.Sprintf("Update of environment %s started (%s).", spec.Name, arn))
+
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVarP(&specFile, "file", "f", "", "Path to the environment spec file, ${NAME} placeholders are expanded from environment variables (required)")
+
+	_ = cmd.MarkFlagRequired("file")
+
+	return cmd
+}
+
+// newDeleteEnvironmentCommand creates a cobra command to delete an MWAA environment.
+func newDeleteEnvironmentCommand(globalOpts *globalOptions) *cobra.Command {
+	var confirmName string
+
+	cmd := &cobra.Command{
+		Use:   "delete [environment]",
+		Short: "Delete an MWAA environment",
+		Long: `Delete an MWAA environment. The environment name must be typed to confirm the deletion,
+either at the prompt or with --confirm.`,
+		SilenceUsage:  true,
+		SilenceErrors: true,
+		Args:          cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := context.Background()
+
+			var mwaaEnvName string
+			if len(args) > 0 {
+				mwaaEnvName = args[0]
+			}
+
+			client, err := initMWAAClient(ctx, globalOpts, &mwaaEnvName)
+			if err != nil {
+				return err
+			}
+
+			if confirmName == "" {
+				cmd.Printf("Environment %s and all of its data will be deleted. This cannot be undone.\n", red(mwaaEnvName))
+
+				confirmName, err = promptText(fmt.Sprintf("Type %q to confirm", mwaaEnvName))
+				if err != nil {
+					return err
+				}
+			}
+
+			if confirmName != mwaaEnvName {
+				return fmt.Errorf("confirmation %q does not match environment name %s", confirmName, mwaaEnvName)
+			}
+
+			if err := client.DeleteEnvironment(ctx, mwaaEnvName); err != nil {
+				return err
+			}
+
+			cmd.Println(green("[SUCCESS]"), fmt.Sprintf("Deletion of environment %s started.", mwaaEnvName))
+
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&confirmName, "confirm", "", "Environment name to confirm the deletion without prompting")
+
+	return cmd
+}
+
+// newWaitEnvironmentCommand creates a cobra command to wait until an MWAA environment reaches a status.
+func newWaitEnvironmentCommand(globalOpts *globalOptions) *cobra.Command {
+	var (
+		until        string
+		timeout      time.Duration
+		pollInterval time.Duration
+	)
+
+	cmd := &cobra.Command{
+		Use:   "wait [environment]",
+		Short: "Wait until an MWAA environment reaches a status",
+		Long: `Wait until an MWAA environment reaches the given status (AVAILABLE, UPDATING or DELETED).
+Fails if the environment ends in CREATE_FAILED or UPDATE_FAILED, or if the timeout expires.`,
+		SilenceUsage:  true,
+		SilenceErrors: true,
+		Args:          cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			target := types.EnvironmentStatus(strings.ToUpper(until))
+
+			switch target {
+			case types.EnvironmentStatusAvailable, types.EnvironmentStatusUpdating, types.EnvironmentStatusDeleted:
+			default:
+				return fmt.Errorf("invalid status: %s, expected AVAILABLE, UPDATING or DELETED", until)
+			}
+
+			ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+			defer stop()
+
+			var mwaaEnvName string
+			if len(args) > 0 {
+				mwaaEnvName = args[0]
+			}
+
+			client, err := initMWAAClient(ctx, globalOpts, &mwaaEnvName)
+			if err != nil {
+				return err
+			}
+
+			if timeout > 0 {
+				var cancel context.CancelFunc
+
+				ctx, cancel = context.WithTimeout(ctx, timeout)
+				defer cancel()
+			}
+
+			environment, err := waitForEnvironment(ctx, cmd, client, mwaaEnvName, target, pollInterval)
+			if err != nil {
+				if errors.Is(err, context.DeadlineExceeded) {
+					return fmt.Errorf("timed out after %s waiting for environment %s to reach %s (last status %s)", timeout, mwaaEnvName, target, environment.Status)
+				}
+
+				return err
+			}
+
+			cmd.Println(green("[SUCCESS]"), fmt.Sprintf("Environment %s is %s.", mwaaEnvName, environment.Status))
+
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&until, "until", string(types.EnvironmentStatusAvailable), "Status to wait for (AVAILABLE, UPDATING or DELETED)")
+	cmd.Flags().DurationVar(&timeout, "timeout", time.Hour, "Maximum time to wait (0 waits indefinitely)")
+	cmd.Flags().DurationVar(&pollInterval, "poll-interval", 10*time.Second, "Initial interval between status checks, doubled up to one minute")
+
+	return cmd
+}
+
+// waitForEnvironment waits until an environment reaches the target status while showing the elapsed time and
+// the current status in a spinner. If the environment fails, the details are printed and an exitCodeError
+// without a message is returned, so they are not printed twice.
+func waitForEnvironment(ctx context.Context, cmd *cobra.Command, client *mwaa.Client, mwaaEnvName string, target types.EnvironmentStatus, pollInterval time.Duration) (*types.Environment, error) {
+	return waitWithSpinner(cmd, mwaaEnvName, fmt.Sprintf("to be %s", target), pollInterval, func(optFn func(o *mwaa.WaitForEnvironmentOptions)) (*types.Environment, error) {
+		return client.WaitForEnvironmentStatus(ctx, mwaaEnvName, target, optFn)
+	})
+}
+
+// waitForEnvironmentUpdate waits until an update requested after the given LastUpdate time has finished,
+// like waitForEnvironment.
+func waitForEnvironmentUpdate(ctx context.Context, cmd *cobra.Command, client *mwaa.Client, mwaaEnvName string, previousUpdate *time.Time, pollInterval time.Duration) (*types.Environment, error) {
+	return waitWithSpinner(cmd, mwaaEnvName, "to finish the update", pollInterval, func(optFn func(o *mwaa.WaitForEnvironmentOptions)) (*types.Environment, error) {
+		return client.WaitForEnvironmentUpdate(ctx, mwaaEnvName, previousUpdate, optFn)
+	})
+}
+
+// waitWithSpinner runs the wait function while showing the elapsed time and the polled status in a spinner.
+func waitWithSpinner(cmd *cobra.Command, mwaaEnvName, description string, pollInterval time.Duration, wait func(optFn func(o *mwaa.WaitForEnvironmentOptions)) (*types.Environment, error)) (*types.Environment, error) {
+	start := time.Now()
+	status := types.EnvironmentStatus("UNKNOWN")
+
+	s := spinner.New(spinner.CharSets[14], 100*time.Millisecond, spinner.WithWriter(cmd.ErrOrStderr()))
+	s.PreUpdate = func(s *spinner.Spinner) {
+		s.Suffix = fmt.Sprintf(" %s (%s elapsed)", status, time.Since(start).Round(time.Second))
+	}
+	s.Prefix = fmt.Sprintf("%s Waiting for environment %s %s... ", cyan("[INFO]"), mwaaEnvName, description)
+	s.Start()
+
+	environment, err := wait(func(o *mwaa.WaitForEnvironmentOptions) {
+		o.MinDelay = pollInterval
+		o.MaxDelay = max(pollInterval, time.Minute)
+		o.OnStatus = func(polled types.EnvironmentStatus) {
+			// The spinner reads the status under its lock in PreUpdate.
+			s.Lock()
+			status = polled
+			s.Unlock()
+		}
+	})
+
+	s.Stop()
+
+	if environment == nil {
+		environment = &types.Environment{Status: status}
+	}
+
+	var failedErr *mwaa.EnvironmentFailedError
+	if errors.As(err, &failedErr) {
+		cmd.PrintErrln(red("[ERROR]"), fmt.Sprintf("Environment %s is %s after %s.", mwaaEnvName, environment.Status, time.Since(start).Round(time.Second)))
+
+		if update := environment.LastUpdate; update != nil && update.Error != nil {
+			cmd.PrintErrln("  Error code:   ", aws.ToString(update.Error.ErrorCode))
+			cmd.PrintErrln("  Error message:", aws.ToString(update.Error.ErrorMessage))
+		}
+
+		// The failure has been reported above, so only the exit code is left to the caller.
+		return environment, &exitCodeError{code: 1}
+	}
+
+	return environment, err
+}
+
+// newPlanEnvironmentCommand creates a cobra command to show the changes a spec file applies to an MWAA environment.
+func newPlanEnvironmentCommand(globalOpts *globalOptions) *cobra.Command {
+	var specFile string
+
+	cmd := &cobra.Command{
+		Use:   "plan",
+		Short: "Show the changes a spec file applies to an MWAA environment",
+		Long: `Compare a desired environment spec with the live MWAA environment and print the differences.
+Exits with code 2 if there are changes, 0 if the environment matches the spec.`,
+		SilenceUsage:  true,
+		SilenceErrors: true,
+		Args:          cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			desired, err := mwaa.LoadEnvironmentSpec(specFile)
+			if err != nil {
+				return err
+			}
+
+			cfg, err := config.NewConfig(globalOpts.profile, globalOpts.region)
+			if err != nil {
+				return err
+			}
+
+			client := mwaa.NewClient(cfg)
+
+			var current *mwaa.EnvironmentSpec
+
+			environment, err := client.GetEnvironment(context.Background(), desired.Name)
+			if err != nil {
+				var notFoundErr *types.ResourceNotFoundException
+				if !errors.As(err, &notFoundErr) {
+					return err
+				}
+			} else {
+				current = mwaa.NewEnvironmentSpec(environment)
+			}
+
+			changes, err := mwaa.DiffEnvironmentSpecs(desired, current)
+			if err != nil {
+				return err
+			}
+
+			if len(changes) == 0 {
+				cmd.Println(green("[SUCCESS]"), fmt.Sprintf("No changes. Environment %s matches the spec.", desired.Name))
+				return nil
+			}
+
+			if current == nil {
+				cmd.Printf("%s environment %q will be created\n", green("+"), desired.Name)
+			} else {
+				cmd.Printf("%s environment %q will be updated in-place\n", yellow("~"), desired.Name)
+			}
+
+			printSpecChanges(cmd, changes)
+
+			return &exitCodeError{code: 2}
+		},
+	}
+
+	cmd.Flags().StringVarP(&specFile, "file", "f", "", "Path to the environment spec file, ${NAME} placeholders are expanded from environment variables (required)")
+
+	_ = cmd.MarkFlagRequired("file")
+
+	return cmd
+}
+
+// printSpecChanges prints spec changes in a Terraform-style format followed by a summary line.
+func printSpecChanges(cmd *cobra.Command, changes []mwaa.SpecChange) {
+	var added, updated, removed int
+
+	for _, change := range changes {
+		var line string
+
+		switch change.Action {
+		case mwaa.SpecChangeAdd:
+			added++
+			line = fmt.Sprintf("  %s %s: %q", green("+"), change.Key, change.Desired)
+		case mwaa.SpecChangeUpdate:
+			updated++
+			line = fmt.Sprintf("  %s %s: %q -> %q", yellow("~"), change.Key, change.Current, change.Desired)
+		case mwaa.SpecChangeRemove:
+			removed++
+			line = fmt.Sprintf("  %s %s: %q", red("-"), change.Key, change.Current)
+		}
+
+		if change.Immutable {
+			line += " " + red("# cannot be changed after creation")
+		}
+
+		cmd.Println(line)
+	}
+
+	cmd.Printf("\nPlan: %d to add, %d to change, %d to remove.\n", added, updated, removed)
+}
+
+// newExportEnvironmentCommand creates a cobra command to export an MWAA environment to a spec file.
+func newExportEnvironmentCommand(globalOpts *globalOptions) *cobra.Command {
+	var (
+		outputFile string
+		templatize bool
+	)
+
+	cmd := &cobra.Command{
+		Use:   "export [environment]",
+		Short: "Export an MWAA environment to a spec file",
+		Long: `Export an MWAA environment to a spec file that can be used with create, update and plan.
+The file is written as JSON if its name ends with .json and as YAML otherwise. Writes YAML to stdout if no file is given.
+With --templatize, the placeholders such as ${EXECUTION_ROLE_ARN} are expanded from environment variables
+when the spec is loaded by create, update and plan.`,
+		SilenceUsage:  true,
+		SilenceErrors: true,
+		Args:          cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := context.Background()
+
+			var mwaaEnvName string
+			if len(args) > 0 {
+				mwaaEnvName = args[0]
+			}
+
+			client, err := initMWAAClient(ctx, globalOpts, &mwaaEnvName)
+			if err != nil {
+				return err
+			}
+
+			environment, err := client.GetEnvironment(ctx, mwaaEnvName)
+			if err != nil {
+				return err
+			}
+
+			spec := mwaa.NewEnvironmentSpec(environment)
+
+			if templatize {
+				spec.Templatize()
+			}
+
+			data, err := formatEnvironmentSpec(spec, strings.HasSuffix(strings.ToLower(outputFile), ".json"))
+			if err != nil {
+				return err
+			}
+
+			if outputFile == "" {
+				cmd.Print(string(data))
+				return nil
+			}
+
+			if err := os.WriteFile(outputFile, data, 0600); err != nil {
+				return fmt.Errorf("failed to write file %s: %w", outputFile, err)
+			}
+
+			cmd.Println(green("[SUCCESS]"), fmt.Sprintf("Environment %s exported to %s.", mwaaEnvName, outputFile))
+
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVarP(&outputFile, "output", "o", "", "Path of the spec file to write (default: stdout)")
+	cmd.Flags().BoolVar(&templatize, "templatize", false, "Replace account-specific fields such as the execution role, bucket and network with ${NAME} placeholders that are expanded from environment variables on load")
+
+	return cmd
+}
+
+// formatEnvironmentSpec renders an environment spec as YAML, or as JSON if asJSON is set.
+func formatEnvironmentSpec(spec *mwaa.EnvironmentSpec, asJSON bool) ([]byte, error) {
+	if asJSON {
+		data, err := json.MarshalIndent(spec, "", "  ")
+		if err != nil {
+			return nil, fmt.Errorf("failed to serialize environment spec: %w", err)
+		}
+
+		return append(data, '\n'), nil
+	}
+
+	var buf bytes.Buffer
+
+	encoder := yaml.NewEncoder(&buf)
+	encoder.SetIndent(2)
+
+	if err := encoder.Encode(spec); err != nil {
+		return nil, fmt.Errorf("failed to serialize environment spec: %w", err)
+	}
+
+	if err := encoder.Close(); err != nil {
+		return nil, fmt.Errorf("failed to serialize environment spec: %w", err)
+	}
+
+	return buf.Bytes(), nil
+}
+
+// newCompareEnvironmentsCommand creates a cobra command to compare two MWAA environments.
+func newCompareEnvironmentsCommand(globalOpts *globalOptions) *cobra.Command {
+	var optsB globalOptions
+
+	cmd := &cobra.Command{
+		Use:   "compare [environment-a] [environment-b]",
+		Short: "Compare two MWAA environments side by side",
+		Long: `Compare the Airflow configuration options, Airflow version, S3 object versions, worker settings and
+installed DAGs of two MWAA environments. The second environment can be in another profile or region.`,
+		SilenceUsage:  true,
+		SilenceErrors: true,
+		Args:          cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if optsB.profile == "" {
+				optsB.profile = globalOpts.profile
+			}
+
+			if optsB.region == "" {
+				optsB.region = globalOpts.region
+			}
+
+			ctx := context.Background()
+
+			specA, dagsA, err := fetchEnvironmentForComparison(ctx, globalOpts, args[0])
+			if err != nil {
+				return err
+			}
+
+			specB, dagsB, err := fetchEnvironmentForComparison(ctx, &optsB, args[1])
+			if err != nil {
+				return err
+			}
+
+			rows, err := compareEnvironmentSpecs(specA, specB)
+			if err != nil {
+				return err
+			}
+
+			if len(rows) == 0 {
+				cmd.Println(green("[SUCCESS]"), "The environment settings are identical.")
+			} else if err := printTable(cmd, []string{"SETTING", args[0], args[1]}, rows); err != nil {
+				return err
+			}
+
+			onlyA, onlyB := compareDagIDs(dagsA, dagsB)
+
+			if len(onlyA) == 0 && len(onlyB) == 0 {
+				cmd.Println(green("[SUCCESS]"), fmt.Sprintf("Both environments have the same %d DAGs.", len(dagsA)))
+				return nil
+			}
+
+			cmd.Println()
+
+			for _, dagID := range onlyA {
+				cmd.Printf("%s %s (only in %s)\n", red("-"), dagID, args[0])
+			}
+
+			for _, dagID := range onlyB {
+				cmd.Printf("%s %s (only in %s)\n", green("+"), dagID, args[1])
+			}
+
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&optsB.profile, "profile-b", "", "AWS profile of the second environment (default: --profile)")
+	cmd.Flags().StringVar(&optsB.region, "region-b", "", "AWS region of the second environment (default: --region)")
+
+	return cmd
+}
+
+// fetchEnvironmentForComparison fetches the spec and the installed DAG IDs of an environment.
+func fetchEnvironmentForComparison(ctx context.Context, opts *globalOptions, mwaaEnvName string) (*mwaa.EnvironmentSpec, []string, error) {
+	client, err := initMWAAClient(ctx, opts, &mwaaEnvName)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	environment, err := client.GetEnvironment(ctx, mwaaEnvName)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get environment %s: %w", mwaaEnvName, err)
+	}
+
+	dags, err := fetchCollection(ctx, client, mwaaEnvName, "/dags", "dags", map[string]any{"only_active": true}, 100, 0, true)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to list DAGs of environment %s: %w", mwaaEnvName, err)
+	}
+
+	dagIDs := make([]string, 0, len(dags))
+	for _, dag := range dags {
+		dagIDs = append(dagIDs, fmt.Sprint(dag["dag_id"]))
+	}
+
+	return mwaa.NewEnvironmentSpec(environment), dagIDs, nil
+}
+
+// comparedSettings are the spec keys compared by "environments compare", in addition to all
+// Airflow configuration options.
+var comparedSettings = map[string]bool{
+	"airflowVersion":               true,
+	"environmentClass":             true,
+	"minWorkers":                   true,
+	"maxWorkers":                   true,
+	"minWebservers":                true,
+	"maxWebservers":                true,
+	"schedulers":                   true,
+	"requirementsS3ObjectVersion":  true,
+	"pluginsS3ObjectVersion":       true,
+	"startupScriptS3ObjectVersion": true,
+}
+
+// compareEnvironmentSpecs returns a row with the setting and both values for every compared setting that differs.
+// Settings missing in one environment are shown as "-".
+func compareEnvironmentSpecs(a, b *mwaa.EnvironmentSpec) ([][]string, error) {
+	flatA, err := a.Flatten()
+	if err != nil {
+		return nil, err
+	}
+
+	flatB, err := b.Flatten()
+	if err != nil {
+		return nil, err
+	}
+
+	keys := map[string]bool{}
+
+	for _, flat := range []map[string]string{flatA, flatB} {
+		for key := range flat {
+			if comparedSettings[key] || strings.HasPrefix(key, "airflowConfigurationOptions.") {
+				keys[key] = true
+			}
+		}
+	}
+
+	value := func(flat map[string]string, key string) string {
+		if v, ok := flat[key]; ok {
+			return v
+		}
+
+		return "-"
+	}
+
+	var rows [][]string
+
+	for key := range keys {
+		if valueA, valueB := value(flatA, key), value(flatB, key); valueA != valueB {
+			rows = append(rows, []string{key, valueA, valueB})
+		}
+	}
+
+	sort.Slice(rows, func(i, j int) bool {
+		return rows[i][0] < rows[j][0]
+	})
+
+	return rows, nil
+}
+
+// compareDagIDs returns the sorted DAG IDs that only exist in a and those that only exist in b.
+func compareDagIDs(a, b []string) ([]string, []string) {
+	difference := func(x, y []string) []string {
+		inY := make(map[string]bool, len(y))
+		for _, id := range y {
+			inY[id] = true
+		}
+
+		var result []string
+
+		for _, id := range x {
+			if !inY[id] {
+				result = append(result, id)
+			}
+		}
+
+		sort.Strings(result)
+
+		return result
+	}
+
+	return difference(a, b), difference(b, a)
+}
+
+// newPublishArtifactCommand creates a cobra command to upload a new version of an artifact
+// and point the MWAA environment to it.
+func newPublishArtifactCommand(globalOpts *globalOptions, artifact mwaa.Artifact, defaultFile string) *cobra.Command {
+	var (
+		file    string
+		wait    bool
+		timeout time.Duration
+	)
+
+	cmd := &cobra.Command{
+		Use:   fmt.Sprintf("publish-%s [environment]", artifact),
+		Short: fmt.Sprintf("Upload %s and update the MWAA environment to the new version", defaultFile),
+		Long: fmt.Sprintf(`Upload %s to the path configured for the environment, then update the environment
+to the new S3 object version. The environment's bucket must have versioning enabled.`, defaultFile),
+		SilenceUsage:  true,
+		SilenceErrors: true,
+		Args:          cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+			defer stop()
+
+			var mwaaEnvName string
+			if len(args) > 0 {
+				mwaaEnvName = args[0]
+			}
+
+			cfg, client, err := initMWAAClientWithConfig(ctx, globalOpts, &mwaaEnvName)
+			if err != nil {
+				return err
+			}
+
+			_, bucket, s3Path, err := getArtifactLocation(ctx, client, mwaaEnvName, artifact)
+			if err != nil {
+				return err
+			}
+
+			version, err := s3.NewClient(cfg).UploadFile(ctx, &s3.UploadFileInput{
+				Bucket:    aws.String(bucket),
+				Key:       aws.String(s3Path),
+				LocalPath: aws.String(file),
+			})
+			if err != nil {
+				return err
+			}
+
+			if version == "" {
+				return fmt.Errorf("bucket %s has no versioning enabled, which MWAA requires", bucket)
+			}
+
+			cmd.Println(cyan("[INFO]"), fmt.Sprintf("Uploaded %s to s3://%s/%s (version %s).", file, bucket, s3Path, version))
+
+			return updateArtifactVersion(ctx, cmd, client, mwaaEnvName, artifact, s3Path, version, wait, timeout)
+		},
+	}
+
+	cmd.Flags().StringVarP(&file, "file", "f", defaultFile, "Path to the local file to upload")
+	cmd.Flags().BoolVar(&wait, "wait", false, "Wait until the environment is AVAILABLE again")
+	cmd.Flags().DurationVar(&timeout, "timeout", time.Hour, "Maximum time to wait with --wait (0 waits indefinitely)")
+
+	return cmd
+}
+
+// updateArtifactVersion points the environment to the given object version of an artifact and optionally
+// waits until the update is complete.
+func updateArtifactVersion(ctx context.Context, cmd *cobra.Command, client *mwaa.Client, mwaaEnvName string, artifact mwaa.Artifact, s3Path, version string, wait bool, timeout time.Duration) error {
+	// The time of the last update identifies the update requested below when waiting for it
+	var previousUpdate *time.Time
+
+	if wait {
+		environment, err := client.GetEnvironment(ctx, mwaaEnvName)
+		if err != nil {
+			return err
+		}
+
+		if environment.LastUpdate != nil {
+			previousUpdate = environment.LastUpdate.CreatedAt
+		}
+	}
+
+	if _, err := client.UpdateEnvironment(ctx, artifact.UpdateEnvironmentInput(mwaaEnvName, s3Path, version)); err != nil {
+		return err
+	}
+
+	if !wait {
+		cmd.Println(green("[SUCCESS]"), fmt.Sprintf("Update of environment %s to %s version %s started.", mwaaEnvName, artifact, version))
+		return nil
+	}
+
+	if timeout > 0 {
+		var cancel context.CancelFunc
+
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+
+	environment, err := waitForEnvironmentUpdate(ctx, cmd, client, mwaaEnvName, previousUpdate, 30*time.Second)
+	if err != nil {
+		if errors.Is(err, context.DeadlineExceeded) {
+			return fmt.Errorf("timed out after %s waiting for the update of environment %s (last status %s)", timeout, mwaaEnvName, environment.Status)
+		}
+
+		return err
+	}
+
+	cmd.Println(green("[SUCCESS]"), fmt.Sprintf("Environment %s is AVAILABLE with %s version %s.", mwaaEnvName, artifact, version))
+
+	return nil
+}
+
+// newArtifactVersionsCommand creates a cobra command to list the S3 object versions of an artifact.
+func newArtifactVersionsCommand(globalOpts *globalOptions) *cobra.Command {
+	var (
+		artifactName string
+		output       string
+	)
+
+	cmd := &cobra.Command{
+		Use:           "versions [environment]",
+		Short:         "List the S3 object versions of the requirements, plugins or startup script",
+		SilenceUsage:  true,
+		SilenceErrors: true,
+		Args:          cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if output != "json" && output != "table" {
+				return fmt.Errorf("invalid output format: %s, expected json or table", output)
+			}
+
+			artifact, err := mwaa.ParseArtifact(artifactName)
+			if err != nil {
+				return err
+			}
+
+			ctx := context.Background()
+
+			var mwaaEnvName string
+			if len(args) > 0 {
+				mwaaEnvName = args[0]
+			}
+
+			cfg, client, err := initMWAAClientWithConfig(ctx, globalOpts, &mwaaEnvName)
+			if err != nil {
+				return err
+			}
+
+			environment, bucket, s3Path, err := getArtifactLocation(ctx, client, mwaaEnvName, artifact)
+			if err != nil {
+				return err
+			}
+
+			versions, err := s3.NewClient(cfg).ListObjectVersions(ctx, &s3.ListObjectVersionsInput{
+				Bucket: aws.String(bucket),
+				Key:    aws.String(s3Path),
+			})
+			if err != nil {
+				return err
+			}
+
+			active := artifact.ObjectVersion(environment)
+
+			if output == "json" {
+				items := make([]map[string]any, 0, len(versions))
+				for _, version := range versions {
+					items = append(items, map[string]any{
+						"version_id":    version.VersionID,
+						"last_modified": version.LastModified.Format(time.RFC3339),
+						"size":          version.Size,
+						"latest":        version.IsLatest,
+						"active":        version.VersionID == active,
+					})
+				}
+
+				return printJSON(cmd, items)
+			}
+
+			rows := make([][]string, 0, len(versions))
+			for _, version := range versions {
+				var marks []string
+				if version.VersionID == active {
+					marks = append(marks, green("active"))
+				}
+
+				if version.IsLatest {
+					marks = append(marks, "latest")
+				}
+
+				rows = append(rows, []string{
+					version.VersionID,
+					version.LastModified.Local().Format(time.DateTime),
+					fmt.Sprint(version.Size),
+					strings.Join(marks, ", "),
+				})
+			}
+
+			cmd.Printf("s3://%s/%s\n", bucket, s3Path)
+
+			return printTable(cmd, []string{"VERSION ID", "LAST MODIFIED", "SIZE", "STATUS"}, rows)
+		},
+	}
+
+	cmd.Flags().StringVar(&artifactName, "artifact", string(mwaa.ArtifactRequirements), "Artifact to list (requirements, plugins or startup)")
+	cmd.Flags().StringVarP(&output, "output", "o", "table", "Output format (json or table)")
+
+	return cmd
+}
+
+// newRollbackArtifactCommand creates a cobra command to point an MWAA environment to a previous version of an artifact.
+func newRollbackArtifactCommand(globalOpts *globalOptions) *cobra.Command {
+	var (
+		artifactName string
+		versionID    string
+		wait         bool
+		timeout      time.Duration
+	)
+
+	cmd := &cobra.Command{
+		Use:           "rollback [environment]",
+		Short:         "Point the environment to a previous version of the requirements, plugins or startup script",
+		SilenceUsage:  true,
+		SilenceErrors: true,
+		Args:          cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			artifact, err := mwaa.ParseArtifact(artifactName)
+			if err != nil {
+				return err
+			}
+
+			ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+			defer stop()
+
+			var mwaaEnvName string
+			if len(args) > 0 {
+				mwaaEnvName = args[0]
+			}
+
+			cfg, client, err := initMWAAClientWithConfig(ctx, globalOpts, &mwaaEnvName)
+			if err != nil {
+				return err
+			}
+
+			environment, bucket, s3Path, err := getArtifactLocation(ctx, client, mwaaEnvName, artifact)
+			if err != nil {
+				return err
+			}
+
+			if artifact.ObjectVersion(environment) == versionID {
+				return fmt.Errorf("environment %s already uses %s version %s", mwaaEnvName, artifact, versionID)
+			}
+
+			versions, err := s3.NewClient(cfg).ListObjectVersions(ctx, &s3.ListObjectVersionsInput{
+				Bucket: aws.String(bucket),
+				Key:    aws.String(s3Path),
+			})
+			if err != nil {
+				return err
+			}
+
+			if !slices.ContainsFunc(versions, func(v s3.ObjectVersion) bool { return v.VersionID == versionID }) {
+				return fmt.Errorf("version %s of s3://%s/%s does not exist", versionID, bucket, s3Path)
+			}
+
+			return updateArtifactVersion(ctx, cmd, client, mwaaEnvName, artifact, s3Path, versionID, wait, timeout)
+		},
+	}
+
+	cmd.Flags().StringVar(&artifactName, "artifact", string(mwaa.ArtifactRequirements), "Artifact to roll back (requirements, plugins or startup)")
+	cmd.Flags().StringVar(&versionID, "to", "", "S3 object version ID to roll back to (required)")
+	cmd.Flags().BoolVar(&wait, "wait", false, "Wait until the environment is AVAILABLE again")
+	cmd.Flags().DurationVar(&timeout, "timeout", time.Hour, "Maximum time to wait with --wait (0 waits indefinitely)")
+
+	_ = cmd.MarkFlagRequired("to")
+
+	return cmd
+}
+
+// getArtifactLocation returns the environment together with the bucket and key of the given artifact.
+func getArtifactLocation(ctx context.Context, client *mwaa.Client, mwaaEnvName string, artifact mwaa.Artifact) (*types.Environment, string, string, error) {
+	environment, err := client.GetEnvironment(ctx, mwaaEnvName)
+	if err != nil {
+		return nil, "", "", err
+	}
+
+	s3Path := artifact.S3Path(environment)
+	if s3Path == "" {
+		return nil, "", "", fmt.Errorf("environment %s has no %s path configured", mwaaEnvName, artifact)
+	}
+
+	bucket, err := s3.BucketNameFromARN(aws.ToString(environment.SourceBucketArn))
+	if err != nil {
+		return nil, "", "", err
+	}
+
+	return environment, bucket, s3Path, nil
+}
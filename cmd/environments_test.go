@@ -0,0 +1,146 @@
+package cmd
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/mwaa/types"
+	"github.com/hupe1980/mwaacli/pkg/mwaa"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEnvironmentHealthChecks(t *testing.T) {
+	t.Run("Available environment", func(t *testing.T) {
+		checks := environmentHealthChecks(&types.Environment{
+			Status:     types.EnvironmentStatusAvailable,
+			LastUpdate: &types.LastUpdate{Status: types.UpdateStatusSuccess},
+		})
+
+		assert.Equal(t, []healthCheck{
+			{Name: "environment-status", Healthy: true, Detail: "AVAILABLE"},
+			{Name: "last-update", Healthy: true, Detail: "SUCCESS"},
+		}, checks)
+	})
+
+	t.Run("Failed update", func(t *testing.T) {
+		checks := environmentHealthChecks(&types.Environment{
+			Status: types.EnvironmentStatusUpdateFailed,
+			LastUpdate: &types.LastUpdate{
+				Status: types.UpdateStatusFailed,
+				Error: &types.UpdateError{
+					ErrorCode:    aws.String("INCORRECT_CONFIGURATION"),
+					ErrorMessage: aws.String("requirements.txt could not be installed"),
+				},
+			},
+		})
+
+		assert.Equal(t, 2, unhealthyChecks(checks))
+		assert.Equal(t, "INCORRECT_CONFIGURATION: requirements.txt could not be installed", checks[1].Detail)
+	})
+}
+
+func TestAirflowHealthChecks(t *testing.T) {
+	checks := airflowHealthChecks(map[string]any{
+		"metadatabase": map[string]any{"status": "healthy"},
+		"scheduler": map[string]any{
+			"status":                     "unhealthy",
+			"latest_scheduler_heartbeat": "2024-01-01T00:00:00+00:00",
+		},
+	})
+
+	assert.Equal(t, []healthCheck{
+		{Name: "metadatabase", Healthy: true, Detail: "healthy"},
+		{Name: "scheduler", Healthy: false, Detail: "unhealthy, last heartbeat 2024-01-01T00:00:00+00:00"},
+	}, checks)
+
+	assert.Equal(t, 2, unhealthyChecks(airflowHealthChecks(map[string]any{})))
+}
+
+func TestImportErrorsHealthCheck(t *testing.T) {
+	assert.True(t, importErrorsHealthCheck(0).Healthy)
+	assert.Equal(t, healthCheck{Name: "import-errors", Healthy: false, Detail: "3 import errors"}, importErrorsHealthCheck(3))
+}
+
+func TestLoggingHealthChecks(t *testing.T) {
+	checks := loggingHealthChecks(&types.LoggingConfiguration{
+		SchedulerLogs: &types.ModuleLoggingConfiguration{Enabled: aws.Bool(true), LogLevel: types.LoggingLevelInfo},
+		TaskLogs:      &types.ModuleLoggingConfiguration{Enabled: aws.Bool(false)},
+	})
+
+	assert.Len(t, checks, 5)
+	assert.Equal(t, healthCheck{Name: "scheduler-logs", Healthy: true, Detail: "enabled (INFO)"}, checks[1])
+	assert.Equal(t, healthCheck{Name: "task-logs", Healthy: false, Detail: "disabled"}, checks[2])
+	assert.Equal(t, 4, unhealthyChecks(checks))
+
+	assert.Equal(t, 5, unhealthyChecks(loggingHealthChecks(nil)))
+}
+
+func TestFormatEnvironmentSpec(t *testing.T) {
+	spec := &mwaa.EnvironmentSpec{
+		Name:       "analytics",
+		MaxWorkers: aws.Int32(10),
+		AirflowConfigurationOptions: map[string]string{
+			"core.default_timezone": "utc",
+		},
+		LoggingConfiguration: &mwaa.LoggingSpec{
+			TaskLogs: &mwaa.ModuleLoggingSpec{Enabled: aws.Bool(true), LogLevel: "INFO"},
+		},
+	}
+
+	for _, asJSON := range []bool{false, true} {
+		data, err := formatEnvironmentSpec(spec, asJSON)
+		assert.NoError(t, err)
+
+		parsed, err := mwaa.ParseEnvironmentSpec(bytes.NewReader(data))
+		assert.NoError(t, err)
+		assert.Equal(t, spec, parsed)
+	}
+
+	data, err := formatEnvironmentSpec(spec, false)
+	assert.NoError(t, err)
+	assert.Contains(t, string(data), "maxWorkers: 10\n")
+}
+
+func TestCompareEnvironmentSpecs(t *testing.T) {
+	a := &mwaa.EnvironmentSpec{
+		Name:                        "stage",
+		AirflowVersion:              aws.String("2.10.3"),
+		MaxWorkers:                  aws.Int32(5),
+		RequirementsS3ObjectVersion: aws.String("v1"),
+		ExecutionRoleArn:            aws.String("arn:aws:iam::111111111111:role/stage"),
+		AirflowConfigurationOptions: map[string]string{
+			"core.default_timezone": "utc",
+			"core.parallelism":      "32",
+		},
+	}
+
+	b := &mwaa.EnvironmentSpec{
+		Name:                        "prod",
+		AirflowVersion:              aws.String("2.10.3"),
+		MaxWorkers:                  aws.Int32(20),
+		RequirementsS3ObjectVersion: aws.String("v2"),
+		ExecutionRoleArn:            aws.String("arn:aws:iam::222222222222:role/prod"),
+		AirflowConfigurationOptions: map[string]string{
+			"core.default_timezone": "utc",
+		},
+	}
+
+	rows, err := compareEnvironmentSpecs(a, b)
+	assert.NoError(t, err)
+	assert.Equal(t, [][]string{
+		{"airflowConfigurationOptions.core.parallelism", "32", "-"},
+		{"maxWorkers", "5", "20"},
+		{"requirementsS3ObjectVersion", "v1", "v2"},
+	}, rows)
+}
+
+func TestCompareDagIDs(t *testing.T) {
+	onlyA, onlyB := compareDagIDs([]string{"c", "a", "shared"}, []string{"shared", "b"})
+	assert.Equal(t, []string{"a", "c"}, onlyA)
+	assert.Equal(t, []string{"b"}, onlyB)
+
+	onlyA, onlyB = compareDagIDs([]string{"a"}, []string{"a"})
+	assert.Empty(t, onlyA)
+	assert.Empty(t, onlyB)
+}
@@ -2,9 +2,11 @@ package cmd
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"os"
 	"os/signal"
+	"path/filepath"
 	"strings"
 	"syscall"
 	"time"
@@ -36,6 +38,7 @@ func newLocalCommand(globalOpts *globalOptions) *cobra.Command {
 	cmd.AddCommand(newStopCommand(globalOpts))
 	cmd.AddCommand(newTestRequirementsCommand(globalOpts))
 	cmd.AddCommand(newPackageRequirementsCommand(globalOpts))
+	cmd.AddCommand(newPackagePluginsCommand(globalOpts))
 	cmd.AddCommand(newTestStartupScriptCommand(globalOpts))
 	cmd.AddCommand(newSyncCommand(globalOpts))
 	cmd.AddCommand(newDiffCommand(globalOpts))
@@ -337,6 +340,38 @@ func newPackageRequirementsCommand(_ *globalOptions) *cobra.Command {
 	return cmd
 }
 
+// newPackagePluginsCommand creates the command to build plugins.zip from a local plugins directory.
+func newPackagePluginsCommand(_ *globalOptions) *cobra.Command {
+	var (
+		pluginsDir string
+		output     string
+	)
+
+	cmd := &cobra.Command{
+		Use:           "package-plugins",
+		Short:         "Package the plugins directory into a plugins.zip file for AWS MWAA",
+		SilenceUsage:  true,
+		SilenceErrors: true,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			cmd.Println(cyan("[INFO]"), fmt.Sprintf("Packaging %s into %s...", pluginsDir, output))
+
+			checksum, err := local.PackagePlugins(pluginsDir, output)
+			if err != nil {
+				return fmt.Errorf("failed to package plugins: %w", err)
+			}
+
+			cmd.Println(green("[SUCCESS]"), fmt.Sprintf("Plugins packaged successfully (SHA-256 %s).", checksum))
+
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&pluginsDir, "plugins-dir", filepath.Join(local.DefaultClonePath, "plugins"), "Path to the plugins directory")
+	cmd.Flags().StringVarP(&output, "output", "o", "plugins.zip", "Path of the zip file to create")
+
+	return cmd
+}
+
 func newTestStartupScriptCommand(globalOpts *globalOptions) *cobra.Command {
 	var (
 		awsCreds bool
@@ -398,11 +433,15 @@ func newSyncCommand(globalOpts *globalOptions) *cobra.Command {
 	var (
 		awsCreds bool
 		roleARN  string
+		force    bool
 	)
 
 	cmd := &cobra.Command{
-		Use:           "sync",
-		Short:         "",
+		Use:   "sync",
+		Short: "Sync the startup script, requirements, plugins and DAGs of the remote MWAA environment",
+		Long: `Download the startup script, requirements, plugins and DAGs the remote MWAA environment runs.
+Plugins and DAGs that were changed locally since the last sync are listed, with a diff for modified text
+files, and only overwritten with --force.`,
 		SilenceUsage:  true,
 		SilenceErrors: true,
 		RunE: func(cmd *cobra.Command, _ []string) error {
@@ -463,7 +502,15 @@ func newSyncCommand(globalOpts *globalOptions) *cobra.Command {
 
 			if pluginsPath := environment.PluginsS3Path; pluginsPath != nil {
 				cmd.Printf("Remote Plugins Path: s3://%s/%s\n", bucketName, aws.ToString(pluginsPath))
-				// TODO
+				if err := syncer.SyncPlugins(ctx, &local.SyncPluginsInput{
+					Bucket:  aws.String(bucketName),
+					Key:     pluginsPath,
+					Version: environment.PluginsS3ObjectVersion,
+					Force:   force,
+				}); err != nil {
+					return syncError(cmd, "plugins", err)
+				}
+				cmd.Println("Plugins synced successfully.")
 			} else {
 				cmd.Println("No remote plugins path configured.")
 			}
@@ -472,7 +519,14 @@ func newSyncCommand(globalOpts *globalOptions) *cobra.Command {
 
 			if dagS3Path := environment.DagS3Path; dagS3Path != nil {
 				cmd.Printf("Remote DAGs Path: s3://%s/%s\n", bucketName, aws.ToString(dagS3Path))
-				// TODO
+				if err := syncer.SyncDags(ctx, &local.SyncDagsInput{
+					Bucket: aws.String(bucketName),
+					Prefix: dagS3Path,
+					Force:  force,
+				}); err != nil {
+					return syncError(cmd, "DAGs", err)
+				}
+				cmd.Println("DAGs synced successfully.")
 			} else {
 				cmd.Println("No remote DAGs path configured.")
 			}
@@ -485,10 +539,59 @@ func newSyncCommand(globalOpts *globalOptions) *cobra.Command {
 
 	cmd.Flags().BoolVar(&awsCreds, "aws-creds", false, "Start the AWS MWAA local runner with AWS credentials")
 	cmd.Flags().StringVar(&roleARN, "role-arn", "", "Specify the IAM Role ARN to use for the AWS MWAA local runner")
+	cmd.Flags().BoolVar(&force, "force", false, "Overwrite local changes to plugins and DAGs")
 
 	return cmd
 }
 
+// syncError prints the local changes that prevented a sync and wraps the error.
+func syncError(cmd *cobra.Command, what string, err error) error {
+	var changesErr *local.LocalChangesError
+	if !errors.As(err, &changesErr) {
+		return fmt.Errorf("failed to sync %s: %w", what, err)
+	}
+
+	cmd.Printf("Local changes in %s since the last sync:\n", changesErr.Dir)
+
+	for _, change := range changesErr.Changes {
+		switch change.Kind {
+		case "added":
+			cmd.Printf("  %s %s (only exists locally)\n", green("+"), change.Path)
+		case "deleted":
+			cmd.Printf("  %s %s (deleted locally)\n", red("-"), change.Path)
+		default:
+			cmd.Printf("  %s %s (differs from remote)\n", yellow("~"), change.Path)
+		}
+	}
+
+	for _, change := range changesErr.Changes {
+		if change.Diff != "" {
+			cmd.Println()
+			printDiff(cmd, change.Diff)
+		}
+	}
+
+	return fmt.Errorf("failed to sync %s: %w; use --force to overwrite them", what, err)
+}
+
+// printDiff prints a unified diff with colored additions, deletions and hunk headers.
+func printDiff(cmd *cobra.Command, diff string) {
+	for _, line := range strings.Split(strings.TrimSuffix(diff, "\n"), "\n") {
+		switch {
+		case strings.HasPrefix(line, "+++"), strings.HasPrefix(line, "---"):
+			cmd.Println(line)
+		case strings.HasPrefix(line, "+"):
+			cmd.Println(green(line))
+		case strings.HasPrefix(line, "-"):
+			cmd.Println(red(line))
+		case strings.HasPrefix(line, "@@"):
+			cmd.Println(cyan(line))
+		default:
+			cmd.Println(line)
+		}
+	}
+}
+
 func newDiffCommand(globalOpts *globalOptions) *cobra.Command {
 	var mwaaEnvName string
 
@@ -2,14 +2,21 @@ package cmd
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"regexp"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/service/mwaa/types"
 	"github.com/hupe1980/mwaacli/pkg/cloudwatch"
-	"github.com/hupe1980/mwaacli/pkg/config"
-	"github.com/hupe1980/mwaacli/pkg/mwaa"
 	"github.com/spf13/cobra"
 )
 
@@ -26,81 +33,328 @@ func newLogsCommand(globalOpts *globalOptions) *cobra.Command {
 	cmd.AddCommand(newLogsTaskCommand(globalOpts))
 	cmd.AddCommand(newLogsWebserverCommand(globalOpts))
 	cmd.AddCommand(newLogsWorkerCommand(globalOpts))
+	cmd.AddCommand(newLogsQueryCommand(globalOpts))
 
 	return cmd
 }
 
-// fetchLogs is a helper function to fetch logs for a specific log type or all logs.
-func fetchLogs(globalOpts *globalOptions, cmd *cobra.Command, ignoredLogs map[string]bool, startTime, endTime, filterPattern, mwaaEnvName string) error {
-	cfg, err := config.NewConfig(globalOpts.profile, globalOpts.region)
+// timeRangeOptions holds the flags that select the time range of log events.
+type timeRangeOptions struct {
+	startTime string
+	endTime   string
+	since     string
+	tz        string
+}
+
+// addFlags registers the time range flags on the given command.
+func (o *timeRangeOptions) addFlags(cmd *cobra.Command) {
+	cmd.Flags().StringVar(&o.startTime, "start-time", "", "Start time for logs: RFC3339, epoch milliseconds or relative like 15m, now-30m or yesterday (default: 1 hour ago)")
+	cmd.Flags().StringVar(&o.endTime, "end-time", "", "End time for logs: RFC3339, epoch milliseconds or relative like 15m, now-30m or today (default: now)")
+	cmd.Flags().StringVar(&o.since, "since", "", "Shorthand for --start-time with a duration ago, e.g. 15m, 2h or 1d")
+	cmd.Flags().StringVar(&o.tz, "tz", "Local", "Timezone for displaying timestamps and resolving dates, e.g. UTC or Europe/Berlin")
+}
+
+// resolve returns the selected time range relative to now and the display location.
+func (o *timeRangeOptions) resolve(now time.Time) (time.Time, time.Time, *time.Location, error) {
+	loc, err := time.LoadLocation(o.tz)
 	if err != nil {
-		return fmt.Errorf("failed to initialize AWS config: %w", err)
+		return time.Time{}, time.Time{}, nil, fmt.Errorf("invalid timezone: %w", err)
 	}
 
-	client := mwaa.NewClient(cfg)
-	ctx := context.Background()
+	now = now.In(loc)
 
-	// Get environment name if not provided
-	if mwaaEnvName == "" {
-		mwaaEnvName, err = getEnvironment(ctx, client)
-		if err != nil {
-			return err
+	startTime := o.startTime
+
+	if o.since != "" {
+		if startTime != "" {
+			return time.Time{}, time.Time{}, nil, fmt.Errorf("--since cannot be combined with --start-time")
+		}
+
+		if _, err := parseRelativeDuration(o.since); err != nil {
+			return time.Time{}, time.Time{}, nil, fmt.Errorf("invalid --since: %w", err)
 		}
+
+		startTime = o.since
 	}
 
-	// Fetch MWAA environment details
-	environment, err := client.GetEnvironment(ctx, mwaaEnvName)
+	start, err := parseTimeOrDefault(startTime, now, now.Add(-1*time.Hour)) // Default: 1 hour ago
 	if err != nil {
-		return fmt.Errorf("failed to get environment: %w", err)
+		return time.Time{}, time.Time{}, nil, fmt.Errorf("invalid start time format: %w", err)
 	}
 
-	// Extract log group ARNs
-	logGroupARNs := extractLogGroupARNs(environment.LoggingConfiguration, ignoredLogs)
+	end, err := parseTimeOrDefault(o.endTime, now, now) // Default: now
+	if err != nil {
+		return time.Time{}, time.Time{}, nil, fmt.Errorf("invalid end time format: %w", err)
+	}
+
+	// Ensure start is before end
+	if start.After(end) {
+		return time.Time{}, time.Time{}, nil, fmt.Errorf("start time must be before end time")
+	}
+
+	return start, end, loc, nil
+}
+
+// logsOptions holds the flags shared by the logs subcommands.
+type logsOptions struct {
+	mwaaEnvName   string
+	timeRange     timeRangeOptions
+	filterPattern string
+	follow        bool
+	pollInterval  time.Duration
+	lookback      time.Duration
+	output        string
+	level         string
+}
+
+// addFlags registers the shared logs flags on the given command.
+func (o *logsOptions) addFlags(cmd *cobra.Command) {
+	o.timeRange.addFlags(cmd)
+	cmd.Flags().StringVar(&o.filterPattern, "filter-pattern", "", "Filter pattern for logs (optional)")
+	cmd.Flags().BoolVarP(&o.follow, "follow", "f", false, "Keep polling for new log events until interrupted")
+	cmd.Flags().DurationVar(&o.pollInterval, "poll-interval", 5*time.Second, "Interval between polls with --follow")
+	cmd.Flags().DurationVar(&o.lookback, "lookback", 30*time.Second, "How far before the newest event each poll with --follow starts, to catch events that arrive late")
+	cmd.Flags().StringVarP(&o.output, "output", "o", "text", "Output format (text, json or ndjson)")
+	cmd.Flags().StringVar(&o.level, "level", "", "Only show events with this Airflow log level or higher (DEBUG, INFO, WARNING, ERROR or CRITICAL); lines such as stack traces have the level of the previous event of their log stream")
+	cmd.Flags().StringVar(&o.mwaaEnvName, "env", "", "MWAA environment name")
+}
 
-	// Parse start and end times safely
-	start, err := parseTimeOrDefault(startTime, time.Now().Add(-1*time.Hour)) // Default: 1 hour ago
+// fetchLogs is a helper function to fetch logs for a specific log type or all logs.
+func fetchLogs(globalOpts *globalOptions, cmd *cobra.Command, ignoredLogs map[string]bool, opts *logsOptions) error {
+	if opts.follow && opts.timeRange.endTime != "" {
+		return fmt.Errorf("--end-time cannot be combined with --follow")
+	}
+
+	start, end, loc, err := opts.timeRange.resolve(time.Now())
 	if err != nil {
-		return fmt.Errorf("invalid start time format: %w", err)
+		return err
 	}
 
-	end, err := parseTimeOrDefault(endTime, time.Now()) // Default: now
+	printer, err := newLogPrinter(cmd, opts.output, opts.level, loc)
 	if err != nil {
-		return fmt.Errorf("invalid end time format: %w", err)
+		return err
 	}
 
-	// Ensure start is before end
-	if start.After(end) {
-		return fmt.Errorf("start time must be before end time")
+	if opts.follow && opts.output == "json" {
+		return fmt.Errorf("--output json cannot be combined with --follow, use ndjson instead")
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	mwaaEnvName := opts.mwaaEnvName
+
+	cfg, client, err := initMWAAClientWithConfig(ctx, globalOpts, &mwaaEnvName)
+	if err != nil {
+		return err
 	}
 
+	// Fetch MWAA environment details
+	environment, err := client.GetEnvironment(ctx, mwaaEnvName)
+	if err != nil {
+		return fmt.Errorf("failed to get environment: %w", err)
+	}
+
+	// Extract log group ARNs
+	logGroupARNs := extractLogGroupARNs(environment.LoggingConfiguration, ignoredLogs)
+
 	// Initialize CloudWatch Logs client
 	cloudwatchClient := cloudwatch.NewClient(cfg)
 
-	// Fetch logs
-	logs, err := cloudwatchClient.FetchLogs(ctx, logGroupARNs, &cloudwatch.LogFilter{
+	if opts.follow {
+		err := cloudwatchClient.FollowLogs(ctx, logGroupARNs, &cloudwatch.LogFilter{
+			StartTime:     aws.Int64(start.UnixMilli()),
+			FilterPattern: aws.String(opts.filterPattern),
+		}, &cloudwatch.FollowOptions{
+			PollInterval: opts.pollInterval,
+			Lookback:     opts.lookback,
+		}, printer.print)
+		if err != nil {
+			return fmt.Errorf("failed to follow logs: %w", err)
+		}
+
+		return nil
+	}
+
+	// Stream logs in timestamp order while they are fetched
+	err = cloudwatchClient.StreamLogs(ctx, logGroupARNs, &cloudwatch.LogFilter{
 		StartTime:     aws.Int64(start.UnixMilli()),
 		EndTime:       aws.Int64(end.UnixMilli()),
-		FilterPattern: aws.String(filterPattern),
-	})
-	if err != nil {
+		FilterPattern: aws.String(opts.filterPattern),
+	}, printer.print)
+	if err != nil && ctx.Err() == nil {
 		return fmt.Errorf("failed to fetch logs: %w", err)
 	}
 
-	// Print logs with timestamp and log group name
-	for _, log := range logs {
-		cmd.Printf("[%s] %s\n", log.LogGroup, log.Message)
+	return printer.close()
+}
+
+// logLevels are the Airflow log levels by severity.
+var logLevels = map[string]int{
+	"DEBUG":    10,
+	"INFO":     20,
+	"WARNING":  30,
+	"ERROR":    40,
+	"CRITICAL": 50,
+}
+
+// airflowLogPrefixRegex matches the prefix of the default Airflow log format,
+// e.g. "[2024-03-27T15:04:05.123+0000] {scheduler_job_runner.py:123} INFO - ".
+var airflowLogPrefixRegex = regexp.MustCompile(`^\[[^\]]+\]\s+\{[^}]*\}\s+([A-Z]+)\s+-\s`)
+
+// parseLogLevel returns the level of an Airflow log message, or an empty string if the message has no Airflow log prefix.
+func parseLogLevel(message string) string {
+	match := airflowLogPrefixRegex.FindStringSubmatch(message)
+	if match == nil {
+		return ""
+	}
+
+	if match[1] == "WARN" {
+		return "WARNING"
+	}
+
+	return match[1]
+}
+
+// logEventOutput is the JSON representation of a log event.
+type logEventOutput struct {
+	Timestamp string `json:"timestamp"`
+	LogGroup  string `json:"log_group"`
+	LogStream string `json:"log_stream"`
+	Level     string `json:"level,omitempty"`
+	Message   string `json:"message"`
+}
+
+// logPrinter prints log events in the selected output format.
+type logPrinter struct {
+	w        io.Writer
+	output   string
+	minLevel int
+	loc      *time.Location
+	printed  int
+	levels   map[string]string // The level of the last event with an Airflow log prefix by log group and stream.
+}
+
+// newLogPrinter creates a logPrinter that writes to the command output. If level is set, only events
+// with an Airflow log level of at least that level are printed.
+func newLogPrinter(cmd *cobra.Command, output, level string, loc *time.Location) (*logPrinter, error) {
+	switch output {
+	case "text", "json", "ndjson":
+	default:
+		return nil, fmt.Errorf("invalid output format: %s, expected text, json or ndjson", output)
+	}
+
+	p := &logPrinter{w: cmd.OutOrStdout(), output: output, loc: loc, levels: map[string]string{}}
+
+	if level != "" {
+		minLevel, ok := logLevels[strings.ToUpper(level)]
+		if !ok {
+			return nil, fmt.Errorf("invalid log level: %s, expected DEBUG, INFO, WARNING, ERROR or CRITICAL", level)
+		}
+
+		p.minLevel = minLevel
+	}
+
+	return p, nil
+}
+
+// print prints a single log event unless it is filtered by level.
+func (p *logPrinter) print(log cloudwatch.LogEvent) error {
+	level := p.level(log)
+	if p.minLevel > 0 && logLevels[level] < p.minLevel {
+		return nil
+	}
+
+	defer func() { p.printed++ }()
+
+	timestamp := formatTimestamp(log.Timestamp, p.loc)
+
+	if p.output == "text" {
+		message := strings.TrimRight(log.Message, "\n")
+
+		switch level {
+		case "ERROR", "CRITICAL":
+			message = red(message)
+		case "WARNING":
+			message = yellow(message)
+		}
+
+		_, err := fmt.Fprintf(p.w, "%s [%s] %s\n", cyan(timestamp), log.LogGroup, message)
+
+		return err
+	}
+
+	event := logEventOutput{
+		Timestamp: timestamp,
+		LogGroup:  log.LogGroup,
+		LogStream: log.LogStream,
+		Level:     level,
+		Message:   log.Message,
+	}
+
+	if p.output == "ndjson" {
+		data, err := json.Marshal(event)
+		if err != nil {
+			return err
+		}
+
+		_, err = fmt.Fprintf(p.w, "%s\n", data)
+
+		return err
 	}
 
-	return nil
+	// The JSON array is written while the events are streamed, so it is opened with the first event.
+	data, err := json.MarshalIndent(event, "  ", "  ")
+	if err != nil {
+		return err
+	}
+
+	separator := ",\n"
+	if p.printed == 0 {
+		separator = "[\n"
+	}
+
+	_, err = fmt.Fprintf(p.w, "%s  %s", separator, data)
+
+	return err
+}
+
+// level returns the Airflow log level of an event. Multi-line records such as stack traces are sent as
+// separate events, so an event without an Airflow log prefix has the level of the previous event with
+// a prefix in the same log stream.
+func (p *logPrinter) level(log cloudwatch.LogEvent) string {
+	stream := log.LogGroup + "/" + log.LogStream
+
+	level := parseLogLevel(log.Message)
+	if level == "" {
+		return p.levels[stream]
+	}
+
+	p.levels[stream] = level
+
+	return level
+}
+
+// close completes the output after the last event.
+func (p *logPrinter) close() error {
+	if p.output != "json" {
+		return nil
+	}
+
+	if p.printed == 0 {
+		_, err := fmt.Fprintln(p.w, "[]")
+		return err
+	}
+
+	_, err := fmt.Fprintln(p.w, "\n]")
+
+	return err
 }
 
 // newLogsAllCommand creates the "logs all" subcommand for fetching all MWAA logs.
 func newLogsAllCommand(globalOpts *globalOptions) *cobra.Command {
 	var (
-		mwaaEnvName   string
-		startTime     string
-		endTime       string
-		filterPattern string
+		opts logsOptions
 
 		// Flags to ignore specific log types
 		ignoreDagProcessing bool
@@ -123,7 +377,7 @@ func newLogsAllCommand(globalOpts *globalOptions) *cobra.Command {
 				"webserver":      ignoreWebserver,
 				"worker":         ignoreWorker,
 			}
-			return fetchLogs(globalOpts, cmd, ignoredLogs, startTime, endTime, filterPattern, mwaaEnvName)
+			return fetchLogs(globalOpts, cmd, ignoredLogs, &opts)
 		},
 	}
 
@@ -135,22 +389,14 @@ func newLogsAllCommand(globalOpts *globalOptions) *cobra.Command {
 	cmd.Flags().BoolVar(&ignoreWorker, "ignore-worker", false, "Ignore worker logs")
 
 	// Other filters
-	cmd.Flags().StringVar(&startTime, "start-time", "", "Start time for logs in RFC3339 format (default: 1 hour ago)")
-	cmd.Flags().StringVar(&endTime, "end-time", "", "End time for logs in RFC3339 format (default: now)")
-	cmd.Flags().StringVar(&filterPattern, "filter-pattern", "", "Filter pattern for logs (optional)")
-	cmd.Flags().StringVar(&mwaaEnvName, "env", "", "MWAA environment name")
+	opts.addFlags(cmd)
 
 	return cmd
 }
 
 // newLogsDagProcessingCommand creates the "logs dag-processing" subcommand for fetching DAG processing logs.
 func newLogsDagProcessingCommand(globalOpts *globalOptions) *cobra.Command {
-	var (
-		mwaaEnvName   string
-		startTime     string
-		endTime       string
-		filterPattern string
-	)
+	var opts logsOptions
 
 	cmd := &cobra.Command{
 		Use:           "dag-processing",
@@ -165,27 +411,19 @@ func newLogsDagProcessingCommand(globalOpts *globalOptions) *cobra.Command {
 				"webserver":      true,
 				"worker":         true,
 			}
-			return fetchLogs(globalOpts, cmd, ignoredLogs, startTime, endTime, filterPattern, mwaaEnvName)
+			return fetchLogs(globalOpts, cmd, ignoredLogs, &opts)
 		},
 	}
 
 	// Flags for filtering logs
-	cmd.Flags().StringVar(&startTime, "start-time", "", "Start time for logs in RFC3339 format (default: 1 hour ago)")
-	cmd.Flags().StringVar(&endTime, "end-time", "", "End time for logs in RFC3339 format (default: now)")
-	cmd.Flags().StringVar(&filterPattern, "filter-pattern", "", "Filter pattern for logs (optional)")
-	cmd.Flags().StringVar(&mwaaEnvName, "env", "", "MWAA environment name")
+	opts.addFlags(cmd)
 
 	return cmd
 }
 
 // newLogsSchedulerCommand creates the "logs scheduler" subcommand for fetching scheduler logs.
 func newLogsSchedulerCommand(globalOpts *globalOptions) *cobra.Command {
-	var (
-		mwaaEnvName   string
-		startTime     string
-		endTime       string
-		filterPattern string
-	)
+	var opts logsOptions
 
 	cmd := &cobra.Command{
 		Use:           "scheduler",
@@ -200,27 +438,19 @@ func newLogsSchedulerCommand(globalOpts *globalOptions) *cobra.Command {
 				"webserver":      true,
 				"worker":         true,
 			}
-			return fetchLogs(globalOpts, cmd, ignoredLogs, startTime, endTime, filterPattern, mwaaEnvName)
+			return fetchLogs(globalOpts, cmd, ignoredLogs, &opts)
 		},
 	}
 
 	// Flags for filtering logs
-	cmd.Flags().StringVar(&startTime, "start-time", "", "Start time for logs in RFC3339 format (default: 1 hour ago)")
-	cmd.Flags().StringVar(&endTime, "end-time", "", "End time for logs in RFC3339 format (default: now)")
-	cmd.Flags().StringVar(&filterPattern, "filter-pattern", "", "Filter pattern for logs (optional)")
-	cmd.Flags().StringVar(&mwaaEnvName, "env", "", "MWAA environment name")
+	opts.addFlags(cmd)
 
 	return cmd
 }
 
 // newLogsTaskCommand creates the "logs task" subcommand for fetching task logs.
 func newLogsTaskCommand(globalOpts *globalOptions) *cobra.Command {
-	var (
-		mwaaEnvName   string
-		startTime     string
-		endTime       string
-		filterPattern string
-	)
+	var opts logsOptions
 
 	cmd := &cobra.Command{
 		Use:           "task",
@@ -235,27 +465,19 @@ func newLogsTaskCommand(globalOpts *globalOptions) *cobra.Command {
 				"webserver":      true,
 				"worker":         true,
 			}
-			return fetchLogs(globalOpts, cmd, ignoredLogs, startTime, endTime, filterPattern, mwaaEnvName)
+			return fetchLogs(globalOpts, cmd, ignoredLogs, &opts)
 		},
 	}
 
 	// Flags for filtering logs
-	cmd.Flags().StringVar(&startTime, "start-time", "", "Start time for logs in RFC3339 format (default: 1 hour ago)")
-	cmd.Flags().StringVar(&endTime, "end-time", "", "End time for logs in RFC3339 format (default: now)")
-	cmd.Flags().StringVar(&filterPattern, "filter-pattern", "", "Filter pattern for logs (optional)")
-	cmd.Flags().StringVar(&mwaaEnvName, "env", "", "MWAA environment name")
+	opts.addFlags(cmd)
 
 	return cmd
 }
 
 // newLogsWebserverCommand creates the "logs webserver" subcommand for fetching webserver logs.
 func newLogsWebserverCommand(globalOpts *globalOptions) *cobra.Command {
-	var (
-		mwaaEnvName   string
-		startTime     string
-		endTime       string
-		filterPattern string
-	)
+	var opts logsOptions
 
 	cmd := &cobra.Command{
 		Use:           "webserver",
@@ -270,27 +492,19 @@ func newLogsWebserverCommand(globalOpts *globalOptions) *cobra.Command {
 				"webserver":      false, // Include only webserver logs
 				"worker":         true,
 			}
-			return fetchLogs(globalOpts, cmd, ignoredLogs, startTime, endTime, filterPattern, mwaaEnvName)
+			return fetchLogs(globalOpts, cmd, ignoredLogs, &opts)
 		},
 	}
 
 	// Flags for filtering logs
-	cmd.Flags().StringVar(&startTime, "start-time", "", "Start time for logs in RFC3339 format (default: 1 hour ago)")
-	cmd.Flags().StringVar(&endTime, "end-time", "", "End time for logs in RFC3339 format (default: now)")
-	cmd.Flags().StringVar(&filterPattern, "filter-pattern", "", "Filter pattern for logs (optional)")
-	cmd.Flags().StringVar(&mwaaEnvName, "env", "", "MWAA environment name")
+	opts.addFlags(cmd)
 
 	return cmd
 }
 
 // newLogsWorkerCommand creates the "logs worker" subcommand for fetching worker logs.
 func newLogsWorkerCommand(globalOpts *globalOptions) *cobra.Command {
-	var (
-		mwaaEnvName   string
-		startTime     string
-		endTime       string
-		filterPattern string
-	)
+	var opts logsOptions
 
 	cmd := &cobra.Command{
 		Use:           "worker",
@@ -305,15 +519,185 @@ func newLogsWorkerCommand(globalOpts *globalOptions) *cobra.Command {
 				"webserver":      true,
 				"worker":         false, // Include only worker logs
 			}
-			return fetchLogs(globalOpts, cmd, ignoredLogs, startTime, endTime, filterPattern, mwaaEnvName)
+			return fetchLogs(globalOpts, cmd, ignoredLogs, &opts)
 		},
 	}
 
 	// Flags for filtering logs
-	cmd.Flags().StringVar(&startTime, "start-time", "", "Start time for logs in RFC3339 format (default: 1 hour ago)")
-	cmd.Flags().StringVar(&endTime, "end-time", "", "End time for logs in RFC3339 format (default: now)")
-	cmd.Flags().StringVar(&filterPattern, "filter-pattern", "", "Filter pattern for logs (optional)")
-	cmd.Flags().StringVar(&mwaaEnvName, "env", "", "MWAA environment name")
+	opts.addFlags(cmd)
+
+	return cmd
+}
+
+// queryPreset is a built-in CloudWatch Logs Insights query.
+type queryPreset struct {
+	description string
+	logTypes    []string // The log types the query runs against.
+	query       string
+}
+
+// queryPresets are the built-in queries available with logs query --preset.
+var queryPresets = map[string]queryPreset{
+	"task-failures": {
+		description: "Failed and retried task instances by DAG and task",
+		logTypes:    []string{"task"},
+		query: `filter @message like /Marking task as (FAILED|UP_FOR_RETRY)/
+| parse @message /dag_id=(?<dag_id>[^,]+), task_id=(?<task_id>[^,]+)/
+| stats count(*) as failures, latest(@timestamp) as last_failure by dag_id, task_id
+| sort failures desc`,
+	},
+	"slow-tasks": {
+		description: "Task instances with the longest run duration in seconds",
+		logTypes:    []string{"scheduler"},
+		query: `filter @message like /TaskInstance Finished/
+| parse @message /dag_id=(?<dag_id>[^,]+), task_id=(?<task_id>[^,]+),.*run_duration=(?<duration>[0-9.]+)/
+| stats max(duration) as max_duration, avg(duration) as avg_duration, count(*) as runs by dag_id, task_id
+| sort max_duration desc
+| limit 25`,
+	},
+	"scheduler-errors": {
+		description: "The latest errors logged by the scheduler",
+		logTypes:    []string{"scheduler"},
+		query: `fields @timestamp, @logStream, @message
+| filter @message like /ERROR/
+| sort @timestamp desc
+| limit 100`,
+	},
+}
+
+// queryPresetsHelp lists the built-in queries for the command help.
+func queryPresetsHelp() string {
+	names := make([]string, 0, len(queryPresets))
+	for name := range queryPresets {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	var sb strings.Builder
+	for _, name := range names {
+		fmt.Fprintf(&sb, "  %-18s %s\n", name, queryPresets[name].description)
+	}
+
+	return sb.String()
+}
+
+// newLogsQueryCommand creates the "logs query" subcommand for running CloudWatch Logs Insights queries.
+func newLogsQueryCommand(globalOpts *globalOptions) *cobra.Command {
+	var (
+		query     string
+		preset    string
+		timeRange timeRangeOptions
+		limit     int32
+		output    string
+	)
+
+	cmd := &cobra.Command{
+		Use:   "query [environment]",
+		Short: "Run a CloudWatch Logs Insights query against the logs of an MWAA environment",
+		Long: `Run a CloudWatch Logs Insights query against the enabled log groups of an MWAA environment
+and print the results once the query is complete.
+
+Built-in queries for --preset:
+` + queryPresetsHelp(),
+		SilenceUsage:  true,
+		SilenceErrors: true,
+		Args:          cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if output != "json" && output != "table" {
+				return fmt.Errorf("invalid output format: %s, expected json or table", output)
+			}
+
+			if (query == "") == (preset == "") {
+				return fmt.Errorf("exactly one of --query or --preset is required")
+			}
+
+			ignoredLogs := map[string]bool{}
+
+			if preset != "" {
+				p, ok := queryPresets[preset]
+				if !ok {
+					return fmt.Errorf("unknown preset: %s, available presets:\n%s", preset, queryPresetsHelp())
+				}
+
+				query = p.query
+
+				for _, logType := range []string{"dag-processing", "scheduler", "task", "webserver", "worker"} {
+					ignoredLogs[logType] = !slices.Contains(p.logTypes, logType)
+				}
+			}
+
+			start, end, _, err := timeRange.resolve(time.Now())
+			if err != nil {
+				return err
+			}
+
+			ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+			defer stop()
+
+			var mwaaEnvName string
+			if len(args) > 0 {
+				mwaaEnvName = args[0]
+			}
+
+			cfg, client, err := initMWAAClientWithConfig(ctx, globalOpts, &mwaaEnvName)
+			if err != nil {
+				return err
+			}
+
+			environment, err := client.GetEnvironment(ctx, mwaaEnvName)
+			if err != nil {
+				return fmt.Errorf("failed to get environment: %w", err)
+			}
+
+			logGroupARNs := extractLogGroupARNs(environment.LoggingConfiguration, ignoredLogs)
+			if len(logGroupARNs) == 0 {
+				return fmt.Errorf("environment %s has no matching log groups enabled", mwaaEnvName)
+			}
+
+			queryInput := &cloudwatch.QueryInput{
+				QueryString: query,
+				StartTime:   start.UnixMilli(),
+				EndTime:     end.UnixMilli(),
+			}
+
+			if limit > 0 {
+				queryInput.Limit = aws.Int32(limit)
+			}
+
+			results, err := cloudwatch.NewClient(cfg).RunQuery(ctx, logGroupARNs, queryInput)
+			if err != nil {
+				return err
+			}
+
+			if output == "json" {
+				return printJSON(cmd, results.Rows)
+			}
+
+			if len(results.Rows) == 0 {
+				cmd.Println(cyan("[INFO]"), "The query returned no results.")
+				return nil
+			}
+
+			rows := make([][]string, 0, len(results.Rows))
+			for _, result := range results.Rows {
+				row := make([]string, 0, len(results.Fields))
+				for _, field := range results.Fields {
+					row = append(row, strings.ReplaceAll(result[field], "\n", " "))
+				}
+
+				rows = append(rows, row)
+			}
+
+			return printTable(cmd, results.Fields, rows)
+		},
+	}
+
+	cmd.Flags().StringVarP(&query, "query", "q", "", "CloudWatch Logs Insights query to run")
+	cmd.Flags().StringVar(&preset, "preset", "", "Name of a built-in query to run")
+	timeRange.addFlags(cmd)
+	cmd.Flags().Int32Var(&limit, "limit", 0, "Maximum number of rows to return (default: the query's limit or 1000)")
+	cmd.Flags().StringVarP(&output, "output", "o", "table", "Output format (json or table)")
 
 	return cmd
 }
@@ -344,11 +728,99 @@ func extractLogGroupARNs(loggingConfig *types.LoggingConfiguration, ignoredLogs
 	return logGroupARNs
 }
 
-// parseTimeOrDefault parses time in RFC3339 format or returns a default value.
-func parseTimeOrDefault(timeStr string, defaultTime time.Time) (time.Time, error) {
+// localTimeLayouts are the timestamp layouts without a timezone that are parsed in the display timezone.
+var localTimeLayouts = []string{"2006-01-02T15:04:05", "2006-01-02 15:04:05", "2006-01-02T15:04", "2006-01-02"}
+
+// parseTimeOrDefault parses a time expression relative to now or returns a default value.
+// Supported expressions are RFC3339 timestamps, dates and date-times in the location of now, epoch milliseconds,
+// "now", "today", "yesterday", durations ago such as "15m" or "2d", and offsets from now such as "now-30m".
+func parseTimeOrDefault(timeStr string, now, defaultTime time.Time) (time.Time, error) {
 	if timeStr == "" {
 		return defaultTime, nil
 	}
 
-	return time.Parse(time.RFC3339, timeStr)
+	if t, err := time.Parse(time.RFC3339, timeStr); err == nil {
+		return t, nil
+	}
+
+	expr := strings.ToLower(strings.TrimSpace(timeStr))
+
+	switch expr {
+	case "now":
+		return now, nil
+	case "today", "yesterday":
+		year, month, day := now.Date()
+		if expr == "yesterday" {
+			day--
+		}
+
+		return time.Date(year, month, day, 0, 0, 0, 0, now.Location()), nil
+	}
+
+	if offset, ok := strings.CutPrefix(expr, "now"); ok && len(offset) > 1 && (offset[0] == '-' || offset[0] == '+') {
+		d, err := parseRelativeDuration(offset[1:])
+		if err != nil {
+			return time.Time{}, err
+		}
+
+		if offset[0] == '-' {
+			d = -d
+		}
+
+		return now.Add(d), nil
+	}
+
+	if epochMillis, err := strconv.ParseInt(expr, 10, 64); err == nil {
+		return time.UnixMilli(epochMillis).In(now.Location()), nil
+	}
+
+	if d, err := parseRelativeDuration(expr); err == nil {
+		return now.Add(-d), nil
+	}
+
+	for _, layout := range localTimeLayouts {
+		if t, err := time.ParseInLocation(layout, timeStr, now.Location()); err == nil {
+			return t, nil
+		}
+	}
+
+	return time.Time{}, fmt.Errorf("invalid time %q, expected RFC3339, epoch milliseconds or a relative time like 15m, now-30m or yesterday", timeStr)
+}
+
+// relativeDurationRegex matches a single component of a relative duration such as "2h" or "1.5d".
+var relativeDurationRegex = regexp.MustCompile(`(\d+(?:\.\d+)?)(ms|s|m|h|d|w)`)
+
+// relativeDurationUnits maps the units of relative durations to their length.
+var relativeDurationUnits = map[string]time.Duration{
+	"ms": time.Millisecond,
+	"s":  time.Second,
+	"m":  time.Minute,
+	"h":  time.Hour,
+	"d":  24 * time.Hour,
+	"w":  7 * 24 * time.Hour,
+}
+
+// parseRelativeDuration parses a duration like time.ParseDuration, but also accepts days ("d") and weeks ("w").
+func parseRelativeDuration(s string) (time.Duration, error) {
+	if s == "" || relativeDurationRegex.ReplaceAllString(s, "") != "" {
+		return 0, fmt.Errorf("invalid duration %q", s)
+	}
+
+	var d time.Duration
+
+	for _, match := range relativeDurationRegex.FindAllStringSubmatch(s, -1) {
+		value, err := strconv.ParseFloat(match[1], 64)
+		if err != nil {
+			return 0, fmt.Errorf("invalid duration %q: %w", s, err)
+		}
+
+		d += time.Duration(value * float64(relativeDurationUnits[match[2]]))
+	}
+
+	return d, nil
+}
+
+// formatTimestamp formats a log event timestamp in milliseconds since the epoch in the given location.
+func formatTimestamp(timestamp int64, loc *time.Location) string {
+	return time.UnixMilli(timestamp).In(loc).Format("2006-01-02T15:04:05.000Z07:00")
 }
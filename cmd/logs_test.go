@@ -1,11 +1,16 @@
 package cmd
 
 import (
+	"bytes"
+	"encoding/json"
+	"strings"
 	"testing"
 	"time"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/service/mwaa/types"
+	"github.com/hupe1980/mwaacli/pkg/cloudwatch"
+	"github.com/spf13/cobra"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -168,7 +173,7 @@ func TestParseTimeOrDefault(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			result, err := parseTimeOrDefault(tt.timeStr, tt.defaultTime)
+			result, err := parseTimeOrDefault(tt.timeStr, time.Now(), tt.defaultTime)
 
 			if tt.expectError {
 				assert.Error(t, err)
@@ -179,3 +184,185 @@ func TestParseTimeOrDefault(t *testing.T) {
 		})
 	}
 }
+
+func TestQueryPresets(t *testing.T) {
+	logTypes := []string{"dag-processing", "scheduler", "task", "webserver", "worker"}
+
+	for name, preset := range queryPresets {
+		t.Run(name, func(t *testing.T) {
+			assert.NotEmpty(t, preset.description)
+			assert.NotEmpty(t, preset.query)
+			assert.Subset(t, logTypes, preset.logTypes)
+			assert.Contains(t, queryPresetsHelp(), name)
+		})
+	}
+}
+
+func TestParseTimeExpressions(t *testing.T) {
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	assert.NoError(t, err)
+
+	now := time.Date(2024, 3, 27, 15, 4, 5, 0, berlin)
+
+	tests := []struct {
+		timeStr      string
+		expectedTime time.Time
+	}{
+		{timeStr: "now", expectedTime: now},
+		{timeStr: "15m", expectedTime: now.Add(-15 * time.Minute)},
+		{timeStr: "2h", expectedTime: now.Add(-2 * time.Hour)},
+		{timeStr: "1d12h", expectedTime: now.Add(-36 * time.Hour)},
+		{timeStr: "1w", expectedTime: now.Add(-7 * 24 * time.Hour)},
+		{timeStr: "now-30m", expectedTime: now.Add(-30 * time.Minute)},
+		{timeStr: "now+1h", expectedTime: now.Add(time.Hour)},
+		{timeStr: "today", expectedTime: time.Date(2024, 3, 27, 0, 0, 0, 0, berlin)},
+		{timeStr: "Yesterday", expectedTime: time.Date(2024, 3, 26, 0, 0, 0, 0, berlin)},
+		{timeStr: "1711551845000", expectedTime: time.UnixMilli(1711551845000).In(berlin)},
+		{timeStr: "2024-03-01", expectedTime: time.Date(2024, 3, 1, 0, 0, 0, 0, berlin)},
+		{timeStr: "2024-03-01T08:30:00", expectedTime: time.Date(2024, 3, 1, 8, 30, 0, 0, berlin)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.timeStr, func(t *testing.T) {
+			result, err := parseTimeOrDefault(tt.timeStr, now, time.Time{})
+			assert.NoError(t, err)
+			assert.True(t, tt.expectedTime.Equal(result), "expected %s, got %s", tt.expectedTime, result)
+		})
+	}
+
+	for _, timeStr := range []string{"now-", "now-abc", "2h foo", "tomorrow"} {
+		t.Run(timeStr, func(t *testing.T) {
+			_, err := parseTimeOrDefault(timeStr, now, time.Time{})
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestTimeRangeOptions(t *testing.T) {
+	now := time.Date(2024, 3, 27, 15, 4, 5, 0, time.UTC)
+
+	o := timeRangeOptions{since: "30m", tz: "UTC"}
+	start, end, loc, err := o.resolve(now)
+	assert.NoError(t, err)
+	assert.Equal(t, now.Add(-30*time.Minute), start)
+	assert.Equal(t, now, end)
+	assert.Equal(t, time.UTC, loc)
+
+	o = timeRangeOptions{since: "30m", startTime: "1h", tz: "UTC"}
+	_, _, _, err = o.resolve(now)
+	assert.Error(t, err)
+
+	o = timeRangeOptions{since: "yesterday", tz: "UTC"}
+	_, _, _, err = o.resolve(now)
+	assert.Error(t, err)
+
+	o = timeRangeOptions{startTime: "now", endTime: "1h", tz: "UTC"}
+	_, _, _, err = o.resolve(now)
+	assert.Error(t, err)
+
+	o = timeRangeOptions{tz: "Mars/Olympus_Mons"}
+	_, _, _, err = o.resolve(now)
+	assert.Error(t, err)
+}
+
+func TestFormatTimestamp(t *testing.T) {
+	assert.Equal(t, "2024-03-27T15:04:05.123Z", formatTimestamp(1711551845123, time.UTC))
+	assert.Equal(t, "2024-03-27T16:04:05.123+01:00", formatTimestamp(1711551845123, time.FixedZone("CET", 3600)))
+}
+
+func TestParseLogLevel(t *testing.T) {
+	tests := []struct {
+		message  string
+		expected string
+	}{
+		{message: "[2024-03-27T15:04:05.123+0000] {scheduler_job_runner.py:123} INFO - Starting the scheduler", expected: "INFO"},
+		{message: "[2024-03-27 15:04:05,123] {taskinstance.py:1234} ERROR - Task failed with exception\nTraceback", expected: "ERROR"},
+		{message: "[2024-03-27T15:04:05.123+0000] {logging_mixin.py:188} WARN - deprecated", expected: "WARNING"},
+		{message: "Traceback (most recent call last):", expected: ""},
+		{message: "INFO - no prefix", expected: ""},
+	}
+
+	for _, tt := range tests {
+		assert.Equal(t, tt.expected, parseLogLevel(tt.message), tt.message)
+	}
+}
+
+func TestLogPrinter(t *testing.T) {
+	logs := []cloudwatch.LogEvent{
+		{Timestamp: 1711551845123, LogGroup: "scheduler", LogStream: "s1", Message: "[2024-03-27T15:04:05.123+0000] {job.py:1} INFO - started"},
+		{Timestamp: 1711551846000, LogGroup: "task", LogStream: "t1", Message: "[2024-03-27T15:04:06.000+0000] {taskinstance.py:2} ERROR - failed"},
+		{Timestamp: 1711551846500, LogGroup: "task", LogStream: "t2", Message: "[2024-03-27T15:04:06.500+0000] {taskinstance.py:3} INFO - retrying"},
+		{Timestamp: 1711551847000, LogGroup: "task", LogStream: "t1", Message: "Traceback (most recent call last):"},
+		{Timestamp: 1711551847000, LogGroup: "task", LogStream: "t3", Message: "line without a previous level"},
+	}
+
+	render := func(t *testing.T, output, level string) string {
+		t.Helper()
+
+		cmd := &cobra.Command{}
+
+		var buf bytes.Buffer
+		cmd.SetOut(&buf)
+
+		p, err := newLogPrinter(cmd, output, level, time.UTC)
+		assert.NoError(t, err)
+
+		for _, log := range logs {
+			assert.NoError(t, p.print(log))
+		}
+
+		assert.NoError(t, p.close())
+
+		return buf.String()
+	}
+
+	t.Run("Text", func(t *testing.T) {
+		out := render(t, "text", "")
+		assert.Contains(t, out, "2024-03-27T15:04:05.123Z [scheduler] [2024-03-27T15:04:05.123+0000] {job.py:1} INFO - started\n")
+		assert.Equal(t, 5, strings.Count(out, "\n"))
+	})
+
+	t.Run("JSON with level filter", func(t *testing.T) {
+		var events []logEventOutput
+		assert.NoError(t, json.Unmarshal([]byte(render(t, "json", "error")), &events))
+		assert.Equal(t, []logEventOutput{
+			{
+				Timestamp: "2024-03-27T15:04:06.000Z",
+				LogGroup:  "task",
+				LogStream: "t1",
+				Level:     "ERROR",
+				Message:   logs[1].Message,
+			},
+			{
+				// The traceback has the level of the previous event of its log stream
+				Timestamp: "2024-03-27T15:04:07.000Z",
+				LogGroup:  "task",
+				LogStream: "t1",
+				Level:     "ERROR",
+				Message:   logs[3].Message,
+			},
+		}, events)
+	})
+
+	t.Run("Empty JSON", func(t *testing.T) {
+		assert.Equal(t, "[]\n", render(t, "json", "critical"))
+	})
+
+	t.Run("NDJSON", func(t *testing.T) {
+		lines := strings.Split(strings.TrimSpace(render(t, "ndjson", "")), "\n")
+		assert.Len(t, lines, 5)
+
+		var event logEventOutput
+		assert.NoError(t, json.Unmarshal([]byte(lines[0]), &event))
+		assert.Equal(t, "INFO", event.Level)
+		assert.Equal(t, "s1", event.LogStream)
+	})
+
+	t.Run("Invalid options", func(t *testing.T) {
+		_, err := newLogPrinter(&cobra.Command{}, "yaml", "", time.UTC)
+		assert.Error(t, err)
+
+		_, err = newLogPrinter(&cobra.Command{}, "text", "TRACE", time.UTC)
+		assert.Error(t, err)
+	})
+}
@@ -0,0 +1,440 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"os"
+	"sort"
+	"strconv"
+
+	"github.com/hupe1980/mwaacli/pkg/mwaa"
+	"github.com/spf13/cobra"
+)
+
+// newPoolsCommand creates the root command for managing pools in MWAA.
+func newPoolsCommand(globalOpts *globalOptions) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "pools",
+		Short: "Manage pools in MWAA",
+		Long:  `Manage pools in Amazon Managed Workflows for Apache Airflow (MWAA).`,
+	}
+
+	cmd.AddCommand(newListPoolsCommand(globalOpts))
+	cmd.AddCommand(newGetPoolCommand(globalOpts))
+	cmd.AddCommand(newSetPoolCommand(globalOpts))
+	cmd.AddCommand(newDeletePoolCommand(globalOpts))
+	cmd.AddCommand(newImportPoolsCommand(globalOpts))
+	cmd.AddCommand(newExportPoolsCommand(globalOpts))
+
+	return cmd
+}
+
+// newListPoolsCommand creates the command to list pools with their utilisation.
+func newListPoolsCommand(globalOpts *globalOptions) *cobra.Command {
+	var (
+		limit       int
+		offset      int
+		all         bool
+		orderBy     string
+		output      string
+		mwaaEnvName string
+	)
+
+	cmd := &cobra.Command{
+		Use:           "list",
+		Short:         "List pools and their utilisation",
+		SilenceUsage:  true,
+		SilenceErrors: true,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			if output != "json" && output != "table" {
+				return fmt.Errorf("invalid output format: %s, expected json or table", output)
+			}
+
+			ctx := context.Background()
+
+			client, err := initMWAAClient(ctx, globalOpts, &mwaaEnvName)
+			if err != nil {
+				return err
+			}
+
+			queryParams := map[string]any{}
+
+			if orderBy != "" {
+				queryParams["order_by"] = orderBy
+			}
+
+			pools, err := fetchCollection(ctx, client, mwaaEnvName, "/pools", "pools", queryParams, limit, offset, all)
+			if err != nil {
+				return err
+			}
+
+			if output == "json" {
+				for _, pool := range pools {
+					pool["utilisation"] = poolUtilisation(pool)
+				}
+
+				return printJSON(cmd, pools)
+			}
+
+			rows := make([][]string, 0, len(pools))
+			for _, pool := range pools {
+				rows = append(rows, []string{
+					fmt.Sprint(pool["name"]),
+					formatPoolSlots(intField(pool, "slots")),
+					strconv.Itoa(intField(pool, "running_slots")),
+					strconv.Itoa(intField(pool, "queued_slots")),
+					strconv.Itoa(intField(pool, "occupied_slots")),
+					formatPoolSlots(intField(pool, "open_slots")),
+					poolUtilisation(pool),
+				})
+			}
+
+			return printTable(cmd, []string{"NAME", "SLOTS", "RUNNING", "QUEUED", "OCCUPIED", "OPEN", "UTILISATION"}, rows)
+		},
+	}
+
+	cmd.Flags().IntVar(&limit, "limit", 100, "The number of items to return")
+	cmd.Flags().IntVar(&offset, "offset", 0, "The number of items to skip before starting to collect the result set")
+	cmd.Flags().BoolVar(&all, "all", false, "Fetch all pages of the result set, using --limit as page size")
+	cmd.Flags().StringVar(&orderBy, "order-by", "", "The name of the field to order the results by. Prefix a field name with - to reverse the sort order")
+	cmd.Flags().StringVarP(&output, "output", "o", "table", "Output format (json or table)")
+
+	cmd.Flags().StringVar(&mwaaEnvName, "env", "", "MWAA environment name")
+
+	return cmd
+}
+
+// newGetPoolCommand creates the command to get a single pool.
+func newGetPoolCommand(globalOpts *globalOptions) *cobra.Command {
+	var mwaaEnvName string
+
+	cmd := &cobra.Command{
+		Use:           "get [pool-name]",
+		Short:         "Get a pool",
+		SilenceUsage:  true,
+		SilenceErrors: true,
+		Args:          cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := context.Background()
+
+			client, err := initMWAAClient(ctx, globalOpts, &mwaaEnvName)
+			if err != nil {
+				return err
+			}
+
+			var response map[string]any
+			if err := client.RestAPIGet(ctx, mwaaEnvName, poolPath(args[0]), nil, &response); err != nil {
+				return err
+			}
+
+			response["utilisation"] = poolUtilisation(response)
+
+			return printJSON(cmd, response)
+		},
+	}
+
+	cmd.Flags().StringVar(&mwaaEnvName, "env", "", "MWAA environment name")
+
+	return cmd
+}
+
+// newSetPoolCommand creates the command to create or update a pool.
+func newSetPoolCommand(globalOpts *globalOptions) *cobra.Command {
+	var (
+		description string
+		mwaaEnvName string
+	)
+
+	cmd := &cobra.Command{
+		Use:           "set [pool-name] [slots]",
+		Short:         "Create or update a pool",
+		SilenceUsage:  true,
+		SilenceErrors: true,
+		Args:          cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			slots, err := strconv.Atoi(args[1])
+			if err != nil {
+				return fmt.Errorf("invalid slots: %w", err)
+			}
+
+			pool := poolDefinition{Slots: slots}
+			if cmd.Flags().Changed("description") {
+				pool.Description = &description
+			}
+
+			ctx := context.Background()
+
+			client, err := initMWAAClient(ctx, globalOpts, &mwaaEnvName)
+			if err != nil {
+				return err
+			}
+
+			response, created, err := setPool(ctx, client, mwaaEnvName, args[0], pool)
+			if err != nil {
+				return err
+			}
+
+			if created {
+				cmd.PrintErrln(green("[SUCCESS]"), fmt.Sprintf("Pool %s created.", args[0]))
+			} else {
+				cmd.PrintErrln(green("[SUCCESS]"), fmt.Sprintf("Pool %s updated.", args[0]))
+			}
+
+			return printJSON(cmd, response)
+		},
+	}
+
+	cmd.Flags().StringVar(&description, "description", "", "Description of the pool")
+
+	cmd.Flags().StringVar(&mwaaEnvName, "env", "", "MWAA environment name")
+
+	return cmd
+}
+
+// newDeletePoolCommand creates the command to delete a pool.
+func newDeletePoolCommand(globalOpts *globalOptions) *cobra.Command {
+	var mwaaEnvName string
+
+	cmd := &cobra.Command{
+		Use:           "delete [pool-name]",
+		Short:         "Delete a pool",
+		SilenceUsage:  true,
+		SilenceErrors: true,
+		Args:          cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := context.Background()
+
+			client, err := initMWAAClient(ctx, globalOpts, &mwaaEnvName)
+			if err != nil {
+				return err
+			}
+
+			if err := client.RestAPIDelete(ctx, mwaaEnvName, poolPath(args[0]), nil); err != nil {
+				return err
+			}
+
+			cmd.Println(green("[SUCCESS]"), fmt.Sprintf("Pool %s deleted.", args[0]))
+
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&mwaaEnvName, "env", "", "MWAA environment name")
+
+	return cmd
+}
+
+// newImportPoolsCommand creates the command to import pools from a JSON file.
+func newImportPoolsCommand(globalOpts *globalOptions) *cobra.Command {
+	var mwaaEnvName string
+
+	cmd := &cobra.Command{
+		Use:           "import [file]",
+		Short:         "Import pools from a JSON file",
+		Long:          `Import pools from a JSON file in the format used by "airflow pools export". Existing pools are updated.`,
+		SilenceUsage:  true,
+		SilenceErrors: true,
+		Args:          cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			content, err := os.ReadFile(args[0])
+			if err != nil {
+				return fmt.Errorf("failed to read file %s: %w", args[0], err)
+			}
+
+			var pools map[string]poolDefinition
+			if err := json.Unmarshal(content, &pools); err != nil {
+				return fmt.Errorf("failed to parse pools file: %w", err)
+			}
+
+			names := make([]string, 0, len(pools))
+			for name := range pools {
+				names = append(names, name)
+			}
+
+			sort.Strings(names)
+
+			ctx := context.Background()
+
+			client, err := initMWAAClient(ctx, globalOpts, &mwaaEnvName)
+			if err != nil {
+				return err
+			}
+
+			var createdCount, updatedCount int
+
+			for _, name := range names {
+				_, created, err := setPool(ctx, client, mwaaEnvName, name, pools[name])
+				if err != nil {
+					return fmt.Errorf("failed to import pool %s: %w", name, err)
+				}
+
+				if created {
+					createdCount++
+
+					cmd.Printf("  created: %s\n", name)
+				} else {
+					updatedCount++
+
+					cmd.Printf("  updated: %s\n", name)
+				}
+			}
+
+			cmd.Println(green("[SUCCESS]"), fmt.Sprintf("%d created, %d updated.", createdCount, updatedCount))
+
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&mwaaEnvName, "env", "", "MWAA environment name")
+
+	return cmd
+}
+
+// newExportPoolsCommand creates the command to export pools to a JSON file.
+func newExportPoolsCommand(globalOpts *globalOptions) *cobra.Command {
+	var mwaaEnvName string
+
+	cmd := &cobra.Command{
+		Use:           "export [file]",
+		Short:         "Export pools to a JSON file",
+		Long:          `Export all pools to a JSON file in the format used by "airflow pools import". Writes to stdout if no file is given.`,
+		SilenceUsage:  true,
+		SilenceErrors: true,
+		Args:          cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := context.Background()
+
+			client, err := initMWAAClient(ctx, globalOpts, &mwaaEnvName)
+			if err != nil {
+				return err
+			}
+
+			items, err := fetchCollection(ctx, client, mwaaEnvName, "/pools", "pools", nil, 100, 0, true)
+			if err != nil {
+				return err
+			}
+
+			pools := make(map[string]poolDefinition, len(items))
+
+			for _, item := range items {
+				pool := poolDefinition{
+					Slots: intField(item, "slots"),
+				}
+
+				if description, ok := item["description"].(string); ok {
+					pool.Description = &description
+				}
+
+				if includeDeferred, ok := item["include_deferred"].(bool); ok {
+					pool.IncludeDeferred = &includeDeferred
+				}
+
+				pools[fmt.Sprint(item["name"])] = pool
+			}
+
+			data, err := json.MarshalIndent(pools, "", "    ")
+			if err != nil {
+				return fmt.Errorf("failed to serialize pools: %w", err)
+			}
+
+			data = append(data, '\n')
+
+			if len(args) == 0 {
+				cmd.Print(string(data))
+				return nil
+			}
+
+			if err := os.WriteFile(args[0], data, 0600); err != nil {
+				return fmt.Errorf("failed to write file %s: %w", args[0], err)
+			}
+
+			cmd.Println(green("[SUCCESS]"), fmt.Sprintf("%d pools exported to %s.", len(pools), args[0]))
+
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&mwaaEnvName, "env", "", "MWAA environment name")
+
+	return cmd
+}
+
+// poolDefinition is the definition of a pool as used by "airflow pools import/export".
+type poolDefinition struct {
+	Slots           int     `json:"slots"`
+	Description     *string `json:"description,omitempty"`
+	IncludeDeferred *bool   `json:"include_deferred,omitempty"`
+}
+
+// setPool updates a pool, creating it if it does not exist yet.
+// Optional fields are left untouched when they are nil. It reports whether the pool was created.
+func setPool(ctx context.Context, client *mwaa.Client, mwaaEnvName, name string, pool poolDefinition) (map[string]any, bool, error) {
+	payload := map[string]any{
+		"name":  name,
+		"slots": pool.Slots,
+	}
+
+	updateMask := []string{"slots"}
+
+	if pool.Description != nil {
+		payload["description"] = *pool.Description
+		updateMask = append(updateMask, "description")
+	}
+
+	if pool.IncludeDeferred != nil {
+		payload["include_deferred"] = *pool.IncludeDeferred
+		updateMask = append(updateMask, "include_deferred")
+	}
+
+	queryParams := map[string]any{
+		"update_mask": updateMask,
+	}
+
+	var response map[string]any
+
+	err := client.RestAPIPatch(ctx, mwaaEnvName, poolPath(name), queryParams, payload, &response)
+	if err == nil {
+		return response, false, nil
+	}
+
+	if !mwaa.IsNotFound(err) {
+		return nil, false, err
+	}
+
+	if err := client.RestAPIPost(ctx, mwaaEnvName, "/pools", nil, payload, &response); err != nil {
+		return nil, false, err
+	}
+
+	return response, true, nil
+}
+
+// poolUtilisation computes the share of occupied slots of a pool as a percentage.
+// Pools with unlimited slots (-1) report "unlimited".
+func poolUtilisation(pool map[string]any) string {
+	slots := intField(pool, "slots")
+	if slots < 0 {
+		return "unlimited"
+	}
+
+	if slots == 0 {
+		return "0%"
+	}
+
+	return fmt.Sprintf("%.0f%%", float64(intField(pool, "occupied_slots"))/float64(slots)*100)
+}
+
+// formatPoolSlots formats a slot count, rendering unlimited slots (-1) as "unlimited".
+func formatPoolSlots(slots int) string {
+	if slots < 0 {
+		return "unlimited"
+	}
+
+	return strconv.Itoa(slots)
+}
+
+// poolPath returns the REST API path of a pool.
+func poolPath(name string) string {
+	return fmt.Sprintf("/pools/%s", url.PathEscape(name))
+}
@@ -0,0 +1,69 @@
+package cmd
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPoolUtilisation(t *testing.T) {
+	tests := []struct {
+		name     string
+		pool     map[string]any
+		expected string
+	}{
+		{
+			name: "Partially occupied pool",
+			pool: map[string]any{
+				"slots":          json.Number("8"),
+				"occupied_slots": json.Number("6"),
+				"running_slots":  json.Number("4"),
+				"queued_slots":   json.Number("2"),
+				"open_slots":     json.Number("2"),
+			},
+			expected: "75%",
+		},
+		{
+			name: "Fully occupied pool",
+			pool: map[string]any{
+				"slots":          json.Number("4"),
+				"occupied_slots": json.Number("4"),
+				"running_slots":  json.Number("3"),
+				"queued_slots":   json.Number("1"),
+				"open_slots":     json.Number("0"),
+			},
+			expected: "100%",
+		},
+		{
+			name: "Empty pool",
+			pool: map[string]any{
+				"slots":      json.Number("0"),
+				"open_slots": json.Number("0"),
+			},
+			expected: "0%",
+		},
+		{
+			name: "Unlimited pool",
+			pool: map[string]any{
+				"slots":      json.Number("-1"),
+				"open_slots": json.Number("-1"),
+			},
+			expected: "unlimited",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, poolUtilisation(tt.pool))
+		})
+	}
+}
+
+func TestIntField(t *testing.T) {
+	item := map[string]any{"slots": json.Number("42"), "name": "n/a"}
+
+	assert.Equal(t, 42, intField(item, "slots"))
+	assert.Equal(t, 0, intField(item, "name"))
+	assert.Equal(t, 0, intField(item, "missing"))
+}
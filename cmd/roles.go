@@ -28,7 +28,13 @@ func newRolesCommand(globalOpts *globalOptions) *cobra.Command {
 
 // newListRolesCommand creates the "list" subcommand for Airflow roles.
 func newListRolesCommand(globalOpts *globalOptions) *cobra.Command {
-	var mwaaEnvName string
+	var (
+		limit       int
+		offset      int
+		all         bool
+		orderBy     string
+		mwaaEnvName string
+	)
 
 	cmd := &cobra.Command{
 		Use:           "list",
@@ -51,17 +57,26 @@ func newListRolesCommand(globalOpts *globalOptions) *cobra.Command {
 				}
 			}
 
-			var response struct {
-				Roles []map[string]any `json:"roles"`
+			queryParams := map[string]any{}
+
+			if orderBy != "" {
+				queryParams["order_by"] = orderBy
 			}
-			if err := client.RestAPIGet(ctx, mwaaEnvName, "/roles", nil, &response); err != nil {
+
+			roles, err := fetchCollection(ctx, client, mwaaEnvName, "/roles", "roles", queryParams, limit, offset, all)
+			if err != nil {
 				return err
 			}
 
-			return printJSON(cmd, response.Roles)
+			return printJSON(cmd, roles)
 		},
 	}
 
+	cmd.Flags().IntVar(&limit, "limit", 100, "The number of items to return")
+	cmd.Flags().IntVar(&offset, "offset", 0, "The number of items to skip before starting to collect the result set")
+	cmd.Flags().BoolVar(&all, "all", false, "Fetch all pages of the result set, using --limit as page size")
+	cmd.Flags().StringVar(&orderBy, "order-by", "", "The name of the field to order the results by. Prefix a field name with - to reverse the sort order")
+
 	cmd.Flags().StringVar(&mwaaEnvName, "env", "", "MWAA environment name")
 
 	return cmd
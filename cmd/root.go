@@ -7,20 +7,24 @@ package cmd
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"os"
 	"strings"
+	"text/tabwriter"
 
 	"github.com/fatih/color"
+	"github.com/hupe1980/mwaacli/pkg/config"
 	"github.com/hupe1980/mwaacli/pkg/mwaa"
 	"github.com/manifoldco/promptui"
 	"github.com/spf13/cobra"
 )
 
 var (
-	cyan  = color.New(color.FgCyan).SprintFunc()
-	green = color.New(color.FgGreen).SprintFunc()
-	red   = color.New(color.FgRed, color.Bold).SprintFunc()
+	cyan   = color.New(color.FgCyan).SprintFunc()
+	green  = color.New(color.FgGreen).SprintFunc()
+	red    = color.New(color.FgRed, color.Bold).SprintFunc()
+	yellow = color.New(color.FgYellow).SprintFunc()
 )
 
 // Execute initializes and runs the root command for the CLI.
@@ -28,11 +32,40 @@ var (
 func Execute(version string) {
 	rootCmd := newRootCmd(version)
 	if err := rootCmd.Execute(); err != nil {
+		var exitErr *exitCodeError
+		if errors.As(err, &exitErr) {
+			if exitErr.err != nil {
+				fmt.Fprintln(os.Stderr, red("[ERROR]"), fmt.Sprintf("%s", exitErr.err))
+			}
+
+			os.Exit(exitErr.code)
+		}
+
 		fmt.Fprintln(os.Stderr, red("[ERROR]"), fmt.Sprintf("%s", err))
 		os.Exit(1)
 	}
 }
 
+// exitCodeError makes the CLI exit with a specific code. The wrapped error is printed if it is not nil.
+type exitCodeError struct {
+	code int
+	err  error
+}
+
+// Error implements the error interface.
+func (e *exitCodeError) Error() string {
+	if e.err == nil {
+		return fmt.Sprintf("exit code %d", e.code)
+	}
+
+	return e.err.Error()
+}
+
+// Unwrap returns the wrapped error.
+func (e *exitCodeError) Unwrap() error {
+	return e.err
+}
+
 // globalOptions holds common flags for AWS interaction.
 type globalOptions struct {
 	profile string // AWS profile name
@@ -60,14 +93,18 @@ func newRootCmd(version string) *cobra.Command {
 	cmd.PersistentFlags().StringVar(&opts.region, "region", "", "AWS region")
 
 	// Add subcommands
+	cmd.AddCommand(newConnectionsCommand(&opts))
+	cmd.AddCommand(newDagRunsCommand(&opts))
 	cmd.AddCommand(newDagsCommand(&opts))
 	cmd.AddCommand(newEnvironmentsCommand(&opts))
 	cmd.AddCommand(newLocalCommand(&opts))
 	cmd.AddCommand(newLogsCommand(&opts))
 	cmd.AddCommand(newOpenCommand(&opts))
+	cmd.AddCommand(newPoolsCommand(&opts))
 	cmd.AddCommand(newRolesCommand(&opts))
 	cmd.AddCommand(newRunCommand(&opts))
 	cmd.AddCommand(newSBCommand(&opts))
+	cmd.AddCommand(newTasksCommand(&opts))
 	cmd.AddCommand(newVariablesCommand(&opts))
 
 	return cmd
@@ -98,6 +135,58 @@ func getEnvironment(ctx context.Context, client *mwaa.Client) (string, error) {
 	return "", fmt.Errorf("no environments found")
 }
 
+// initMWAAClient sets up an MWAA client and resolves the environment name if it is not set.
+func initMWAAClient(ctx context.Context, globalOpts *globalOptions, mwaaEnvName *string) (*mwaa.Client, error) {
+	_, client, err := initMWAAClientWithConfig(ctx, globalOpts, mwaaEnvName)
+	return client, err
+}
+
+// initMWAAClientWithConfig is like initMWAAClient, but also returns the AWS configuration
+// for commands that need clients of other services.
+func initMWAAClientWithConfig(ctx context.Context, globalOpts *globalOptions, mwaaEnvName *string) (*config.Config, *mwaa.Client, error) {
+	cfg, err := config.NewConfig(globalOpts.profile, globalOpts.region)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	client := mwaa.NewClient(cfg)
+
+	if *mwaaEnvName == "" {
+		*mwaaEnvName, err = getEnvironment(ctx, client)
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+
+	return cfg, client, nil
+}
+
+// fetchCollection retrieves a page of a REST API collection starting at offset.
+// If all is set, it follows the pages until every item of the collection is fetched.
+func fetchCollection(ctx context.Context, client *mwaa.Client, mwaaEnvName, path, collectionKey string, queryParams map[string]any, limit, offset int, all bool) ([]map[string]any, error) {
+	paginator := mwaa.NewRestAPIPaginator(client, mwaaEnvName, path, collectionKey, queryParams, func(o *mwaa.RestAPIPaginatorOptions) {
+		o.Limit = limit
+		o.Offset = offset
+	})
+
+	if !all {
+		return paginator.NextPage(ctx)
+	}
+
+	var items []map[string]any
+
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		items = append(items, page...)
+	}
+
+	return items, nil
+}
+
 // chooseEnvironment prompts the user to select an MWAA environment from a list.
 // It uses a fuzzy search to filter environments based on user input.
 func chooseEnvironment(environments []string) (string, error) {
@@ -131,6 +220,33 @@ func chooseEnvironment(environments []string) (string, error) {
 	return environments[i], nil
 }
 
+// confirm prompts the user with a yes/no question and reports whether it was accepted.
+func confirm(label string) (bool, error) {
+	prompt := promptui.Prompt{
+		Label:     label,
+		IsConfirm: true,
+	}
+
+	if _, err := prompt.Run(); err != nil {
+		if errors.Is(err, promptui.ErrAbort) {
+			return false, nil
+		}
+
+		return false, err
+	}
+
+	return true, nil
+}
+
+// promptText prompts the user to enter a line of text and returns it.
+func promptText(label string) (string, error) {
+	prompt := promptui.Prompt{
+		Label: label,
+	}
+
+	return prompt.Run()
+}
+
 // printJSON prints the given value as a formatted JSON string to the command output.
 // It returns an error if the value cannot be marshaled to JSON.
 func printJSON(cmd *cobra.Command, v any) error {
@@ -143,3 +259,27 @@ func printJSON(cmd *cobra.Command, v any) error {
 
 	return nil
 }
+
+// printTable prints the rows as an aligned table with the given headers to the command output.
+func printTable(cmd *cobra.Command, headers []string, rows [][]string) error {
+	w := tabwriter.NewWriter(cmd.OutOrStdout(), 0, 0, 2, ' ', 0)
+
+	fmt.Fprintln(w, strings.Join(headers, "\t"))
+
+	for _, row := range rows {
+		fmt.Fprintln(w, strings.Join(row, "\t"))
+	}
+
+	return w.Flush()
+}
+
+// intField returns the numeric field of a REST API response item as an int.
+// Missing or non-numeric fields yield 0.
+func intField(item map[string]any, key string) int {
+	n, err := mwaa.ToInt(item[key])
+	if err != nil {
+		return 0
+	}
+
+	return n
+}
@@ -0,0 +1,213 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+
+	"github.com/hupe1980/mwaacli/pkg/mwaa"
+	"github.com/spf13/cobra"
+)
+
+// newTasksCommand creates the root command for inspecting task instances in MWAA.
+func newTasksCommand(globalOpts *globalOptions) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "tasks",
+		Short: "Inspect task instances in MWAA",
+		Long:  `Inspect task instances and their logs in Amazon Managed Workflows for Apache Airflow (MWAA).`,
+	}
+
+	cmd.AddCommand(newListTasksCommand(globalOpts))
+	cmd.AddCommand(newGetTaskCommand(globalOpts))
+	cmd.AddCommand(newTaskLogsCommand(globalOpts))
+
+	return cmd
+}
+
+// newListTasksCommand creates the command to list the task instances of a DAG run.
+func newListTasksCommand(globalOpts *globalOptions) *cobra.Command {
+	var (
+		limit       int
+		offset      int
+		all         bool
+		states      []string
+		mwaaEnvName string
+	)
+
+	cmd := &cobra.Command{
+		Use:           "list [dag-id] [run-id]",
+		Short:         "List task instances of a DAG run",
+		SilenceUsage:  true,
+		SilenceErrors: true,
+		Args:          cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := context.Background()
+
+			client, err := initMWAAClient(ctx, globalOpts, &mwaaEnvName)
+			if err != nil {
+				return err
+			}
+
+			queryParams := map[string]any{}
+
+			if len(states) > 0 {
+				queryParams["state"] = states
+			}
+
+			taskInstances, err := fetchCollection(ctx, client, mwaaEnvName, dagRunPath(args[0], args[1])+"/taskInstances", "task_instances", queryParams, limit, offset, all)
+			if err != nil {
+				return err
+			}
+
+			return printJSON(cmd, taskInstances)
+		},
+	}
+
+	cmd.Flags().IntVar(&limit, "limit", 100, "The number of items to return")
+	cmd.Flags().IntVar(&offset, "offset", 0, "The number of items to skip before starting to collect the result set")
+	cmd.Flags().BoolVar(&all, "all", false, "Fetch all pages of the result set, using --limit as page size")
+	cmd.Flags().StringSliceVar(&states, "state", nil, "List of states to filter results (e.g. running,success,failed)")
+
+	cmd.Flags().StringVar(&mwaaEnvName, "env", "", "MWAA environment name")
+
+	return cmd
+}
+
+// newGetTaskCommand creates the command to get details of a specific task instance.
+func newGetTaskCommand(globalOpts *globalOptions) *cobra.Command {
+	var (
+		mapIndex    int
+		mwaaEnvName string
+	)
+
+	cmd := &cobra.Command{
+		Use:           "get [dag-id] [run-id] [task-id]",
+		Short:         "Get details of a specific task instance",
+		SilenceUsage:  true,
+		SilenceErrors: true,
+		Args:          cobra.ExactArgs(3),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := context.Background()
+
+			client, err := initMWAAClient(ctx, globalOpts, &mwaaEnvName)
+			if err != nil {
+				return err
+			}
+
+			var response map[string]any
+			if err := client.RestAPIGet(ctx, mwaaEnvName, taskInstancePath(args[0], args[1], args[2], mapIndex), nil, &response); err != nil {
+				return err
+			}
+
+			return printJSON(cmd, response)
+		},
+	}
+
+	cmd.Flags().IntVar(&mapIndex, "map-index", -1, "Map index of a mapped task instance")
+
+	cmd.Flags().StringVar(&mwaaEnvName, "env", "", "MWAA environment name")
+
+	return cmd
+}
+
+// newTaskLogsCommand creates the command to fetch the log of a task instance attempt.
+func newTaskLogsCommand(globalOpts *globalOptions) *cobra.Command {
+	var (
+		tryNumber   int
+		mapIndex    int
+		mwaaEnvName string
+	)
+
+	cmd := &cobra.Command{
+		Use:           "logs [dag-id] [run-id] [task-id]",
+		Short:         "Fetch the log of a task instance attempt",
+		SilenceUsage:  true,
+		SilenceErrors: true,
+		Args:          cobra.ExactArgs(3),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			dagID, dagRunID, taskID := args[0], args[1], args[2]
+
+			ctx := context.Background()
+
+			client, err := initMWAAClient(ctx, globalOpts, &mwaaEnvName)
+			if err != nil {
+				return err
+			}
+
+			// Default to the latest attempt
+			if tryNumber <= 0 {
+				var ti struct {
+					TryNumber int `document:"try_number"`
+				}
+				if err := client.RestAPIGet(ctx, mwaaEnvName, taskInstancePath(dagID, dagRunID, taskID, mapIndex), nil, &ti); err != nil {
+					return err
+				}
+
+				tryNumber = max(ti.TryNumber, 1)
+			}
+
+			return fetchTaskLog(ctx, client, mwaaEnvName, dagID, dagRunID, taskID, mapIndex, tryNumber, func(content string) {
+				cmd.Print(content)
+			})
+		},
+	}
+
+	cmd.Flags().IntVar(&tryNumber, "try", 0, "Attempt number of the task instance (default: latest attempt)")
+	cmd.Flags().IntVar(&mapIndex, "map-index", -1, "Map index of a mapped task instance")
+
+	cmd.Flags().StringVar(&mwaaEnvName, "env", "", "MWAA environment name")
+
+	return cmd
+}
+
+// fetchTaskLog retrieves the log of a task instance attempt and passes each chunk to the handler.
+// It follows the continuation token until the log is complete.
+func fetchTaskLog(ctx context.Context, client *mwaa.Client, mwaaEnvName, dagID, dagRunID, taskID string, mapIndex, tryNumber int, handler func(content string)) error {
+	path := fmt.Sprintf("%s/taskInstances/%s/logs/%d", dagRunPath(dagID, dagRunID), url.PathEscape(taskID), tryNumber)
+
+	var token string
+
+	for {
+		queryParams := map[string]any{
+			"full_content": false,
+		}
+
+		if mapIndex >= 0 {
+			queryParams["map_index"] = mapIndex
+		}
+
+		if token != "" {
+			queryParams["token"] = token
+		}
+
+		var response struct {
+			Content           string `document:"content"`
+			ContinuationToken string `document:"continuation_token"`
+		}
+		if err := client.RestAPIGet(ctx, mwaaEnvName, path, queryParams, &response); err != nil {
+			return err
+		}
+
+		if response.Content != "" {
+			handler(response.Content)
+		}
+
+		// The log is complete once no new content or continuation token is returned
+		if response.Content == "" || response.ContinuationToken == "" || response.ContinuationToken == token {
+			return nil
+		}
+
+		token = response.ContinuationToken
+	}
+}
+
+// taskInstancePath returns the REST API path of a task instance.
+func taskInstancePath(dagID, dagRunID, taskID string, mapIndex int) string {
+	path := fmt.Sprintf("%s/taskInstances/%s", dagRunPath(dagID, dagRunID), url.PathEscape(taskID))
+
+	if mapIndex >= 0 {
+		path = fmt.Sprintf("%s/%d", path, mapIndex)
+	}
+
+	return path
+}
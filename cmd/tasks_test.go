@@ -0,0 +1,47 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTaskInstancePath(t *testing.T) {
+	tests := []struct {
+		name     string
+		taskID   string
+		mapIndex int
+		expected string
+	}{
+		{
+			name:     "Unmapped task instance",
+			taskID:   "extract",
+			mapIndex: -1,
+			expected: "/dags/example/dagRuns/run_1/taskInstances/extract",
+		},
+		{
+			name:     "Mapped task instance",
+			taskID:   "extract",
+			mapIndex: 3,
+			expected: "/dags/example/dagRuns/run_1/taskInstances/extract/3",
+		},
+		{
+			name:     "Task in a task group",
+			taskID:   "group.extract",
+			mapIndex: -1,
+			expected: "/dags/example/dagRuns/run_1/taskInstances/group.extract",
+		},
+		{
+			name:     "Task ID with reserved characters",
+			taskID:   "extract/a b?",
+			mapIndex: -1,
+			expected: "/dags/example/dagRuns/run_1/taskInstances/extract%2Fa%20b%3F",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, taskInstancePath("example", "run_1", tt.taskID, tt.mapIndex))
+		})
+	}
+}
@@ -1,7 +1,16 @@
 package cmd
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"reflect"
+	"sort"
+	"strings"
 
 	"github.com/hupe1980/mwaacli/pkg/config"
 	"github.com/hupe1980/mwaacli/pkg/mwaa"
@@ -16,6 +25,11 @@ func newVariablesCommand(globalOpts *globalOptions) *cobra.Command {
 	}
 
 	cmd.AddCommand(newListVariablesCommand(globalOpts))
+	cmd.AddCommand(newGetVariableCommand(globalOpts))
+	cmd.AddCommand(newSetVariableCommand(globalOpts))
+	cmd.AddCommand(newDeleteVariableCommand(globalOpts))
+	cmd.AddCommand(newImportVariablesCommand(globalOpts))
+	cmd.AddCommand(newExportVariablesCommand(globalOpts))
 
 	return cmd
 }
@@ -24,6 +38,7 @@ func newListVariablesCommand(globalOpts *globalOptions) *cobra.Command {
 	var (
 		limit       int
 		offset      int
+		all         bool
 		orderBy     string
 		mwaaEnvName string
 	)
@@ -47,31 +62,489 @@ func newListVariablesCommand(globalOpts *globalOptions) *cobra.Command {
 				}
 			}
 
-			queryParams := map[string]any{
-				"limit":  limit,
-				"offset": offset,
-			}
+			queryParams := map[string]any{}
 
 			if orderBy != "" {
 				queryParams["order_by"] = orderBy
 			}
 
-			var response struct {
-				Variables []map[string]any `json:"variables"`
-			}
-			if err := client.RestAPIGet(ctx, mwaaEnvName, "/variables", queryParams, &response); err != nil {
+			variables, err := fetchCollection(ctx, client, mwaaEnvName, "/variables", "variables", queryParams, limit, offset, all)
+			if err != nil {
 				return err
 			}
 
-			return printJSON(cmd, response.Variables)
+			return printJSON(cmd, variables)
 		},
 	}
 
 	cmd.Flags().IntVar(&limit, "limit", 100, "The number of items to return")
 	cmd.Flags().IntVar(&offset, "offset", 0, "The number of items to skip before starting to collect the result set")
+	cmd.Flags().BoolVar(&all, "all", false, "Fetch all pages of the result set, using --limit as page size")
 	cmd.Flags().StringVar(&orderBy, "order-by", "", "The name of the field to order the results by. Prefix a field name with - to reverse the sort order")
 
 	cmd.Flags().StringVar(&mwaaEnvName, "env", "", "MWAA environment name")
 
 	return cmd
 }
+
+// newGetVariableCommand creates the command to get a single variable.
+func newGetVariableCommand(globalOpts *globalOptions) *cobra.Command {
+	var mwaaEnvName string
+
+	cmd := &cobra.Command{
+		Use:           "get [key]",
+		Short:         "Get a variable",
+		SilenceUsage:  true,
+		SilenceErrors: true,
+		Args:          cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := context.Background()
+
+			client, err := initMWAAClient(ctx, globalOpts, &mwaaEnvName)
+			if err != nil {
+				return err
+			}
+
+			var response map[string]any
+			if err := client.RestAPIGet(ctx, mwaaEnvName, variablePath(args[0]), nil, &response); err != nil {
+				return err
+			}
+
+			return printJSON(cmd, response)
+		},
+	}
+
+	cmd.Flags().StringVar(&mwaaEnvName, "env", "", "MWAA environment name")
+
+	return cmd
+}
+
+// newSetVariableCommand creates the command to create or update a variable.
+func newSetVariableCommand(globalOpts *globalOptions) *cobra.Command {
+	var (
+		file        string
+		description string
+		mwaaEnvName string
+	)
+
+	cmd := &cobra.Command{
+		Use:   "set [key] [value]",
+		Short: "Create or update a variable",
+		Long: `Create or update a variable. The value is taken from the second argument, from the file given by --file,
+or from stdin if the value is omitted or "-".`,
+		SilenceUsage:  true,
+		SilenceErrors: true,
+		Args:          cobra.RangeArgs(1, 2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			key := args[0]
+
+			var value string
+
+			switch {
+			case file != "":
+				if len(args) > 1 {
+					return fmt.Errorf("a value argument cannot be combined with --file")
+				}
+
+				content, err := os.ReadFile(file)
+				if err != nil {
+					return fmt.Errorf("failed to read file %s: %w", file, err)
+				}
+
+				value = string(content)
+			case len(args) > 1 && args[1] != "-":
+				value = args[1]
+			default:
+				content, err := io.ReadAll(cmd.InOrStdin())
+				if err != nil {
+					return fmt.Errorf("failed to read value from stdin: %w", err)
+				}
+
+				value = strings.TrimSuffix(string(content), "\n")
+			}
+
+			ctx := context.Background()
+
+			client, err := initMWAAClient(ctx, globalOpts, &mwaaEnvName)
+			if err != nil {
+				return err
+			}
+
+			var descriptionPtr *string
+			if cmd.Flags().Changed("description") {
+				descriptionPtr = &description
+			}
+
+			response, created, err := setVariable(ctx, client, mwaaEnvName, key, value, descriptionPtr)
+			if err != nil {
+				return err
+			}
+
+			if created {
+				cmd.PrintErrln(green("[SUCCESS]"), fmt.Sprintf("Variable %s created.", key))
+			} else {
+				cmd.PrintErrln(green("[SUCCESS]"), fmt.Sprintf("Variable %s updated.", key))
+			}
+
+			return printJSON(cmd, response)
+		},
+	}
+
+	cmd.Flags().StringVarP(&file, "file", "f", "", "Read the value from a file")
+	cmd.Flags().StringVar(&description, "description", "", "Description of the variable")
+
+	cmd.Flags().StringVar(&mwaaEnvName, "env", "", "MWAA environment name")
+
+	return cmd
+}
+
+// newDeleteVariableCommand creates the command to delete a variable.
+func newDeleteVariableCommand(globalOpts *globalOptions) *cobra.Command {
+	var mwaaEnvName string
+
+	cmd := &cobra.Command{
+		Use:           "delete [key]",
+		Short:         "Delete a variable",
+		SilenceUsage:  true,
+		SilenceErrors: true,
+		Args:          cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := context.Background()
+
+			client, err := initMWAAClient(ctx, globalOpts, &mwaaEnvName)
+			if err != nil {
+				return err
+			}
+
+			if err := client.RestAPIDelete(ctx, mwaaEnvName, variablePath(args[0]), nil); err != nil {
+				return err
+			}
+
+			cmd.Println(green("[SUCCESS]"), fmt.Sprintf("Variable %s deleted.", args[0]))
+
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&mwaaEnvName, "env", "", "MWAA environment name")
+
+	return cmd
+}
+
+// newImportVariablesCommand creates the command to import variables from a JSON file.
+func newImportVariablesCommand(globalOpts *globalOptions) *cobra.Command {
+	var (
+		overwrite    bool
+		skipExisting bool
+		mwaaEnvName  string
+	)
+
+	cmd := &cobra.Command{
+		Use:   "import [file]",
+		Short: "Import variables from a JSON file",
+		Long: `Import variables from a JSON file in the format used by "airflow variables export".
+Existing variables with a different value cause the import to fail unless --overwrite or --skip-existing is set.`,
+		SilenceUsage:  true,
+		SilenceErrors: true,
+		Args:          cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if overwrite && skipExisting {
+				return fmt.Errorf("--overwrite and --skip-existing cannot be combined")
+			}
+
+			content, err := os.ReadFile(args[0])
+			if err != nil {
+				return fmt.Errorf("failed to read file %s: %w", args[0], err)
+			}
+
+			desired, err := parseVariablesFile(content)
+			if err != nil {
+				return err
+			}
+
+			ctx := context.Background()
+
+			client, err := initMWAAClient(ctx, globalOpts, &mwaaEnvName)
+			if err != nil {
+				return err
+			}
+
+			existing := make(map[string]string, len(desired))
+
+			for key := range desired {
+				var variable struct {
+					Value string `document:"value"`
+				}
+				if err := client.RestAPIGet(ctx, mwaaEnvName, variablePath(key), nil, &variable); err != nil {
+					if mwaa.IsNotFound(err) {
+						continue
+					}
+
+					return err
+				}
+
+				existing[key] = variable.Value
+			}
+
+			plan, err := planVariableImport(desired, existing, overwrite, skipExisting)
+			if err != nil {
+				return err
+			}
+
+			for _, key := range append(plan.create, plan.update...) {
+				if _, _, err := setVariable(ctx, client, mwaaEnvName, key, desired[key], nil); err != nil {
+					return fmt.Errorf("failed to import variable %s: %w", key, err)
+				}
+			}
+
+			cmd.Println(green("[SUCCESS]"), fmt.Sprintf("%d created, %d updated, %d skipped, %d unchanged.",
+				len(plan.create), len(plan.update), len(plan.skip), len(plan.unchanged)))
+
+			printKeys := func(label string, keys []string) {
+				for _, key := range keys {
+					cmd.Printf("  %s %s\n", label, key)
+				}
+			}
+
+			printKeys("created:  ", plan.create)
+			printKeys("updated:  ", plan.update)
+			printKeys("skipped:  ", plan.skip)
+			printKeys("unchanged:", plan.unchanged)
+
+			return nil
+		},
+	}
+
+	cmd.Flags().BoolVar(&overwrite, "overwrite", false, "Overwrite existing variables")
+	cmd.Flags().BoolVar(&skipExisting, "skip-existing", false, "Skip existing variables")
+
+	cmd.Flags().StringVar(&mwaaEnvName, "env", "", "MWAA environment name")
+
+	return cmd
+}
+
+// newExportVariablesCommand creates the command to export variables to a JSON file.
+func newExportVariablesCommand(globalOpts *globalOptions) *cobra.Command {
+	var mwaaEnvName string
+
+	cmd := &cobra.Command{
+		Use:           "export [file]",
+		Short:         "Export variables to a JSON file",
+		Long:          `Export all variables to a JSON file in the format used by "airflow variables import". Writes to stdout if no file is given.`,
+		SilenceUsage:  true,
+		SilenceErrors: true,
+		Args:          cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := context.Background()
+
+			client, err := initMWAAClient(ctx, globalOpts, &mwaaEnvName)
+			if err != nil {
+				return err
+			}
+
+			variables, err := fetchCollection(ctx, client, mwaaEnvName, "/variables", "variables", nil, 100, 0, true)
+			if err != nil {
+				return err
+			}
+
+			values := make(map[string]string, len(variables))
+
+			// The collection endpoint may redact sensitive values, so each value is fetched individually
+			for _, item := range variables {
+				key, _ := item["key"].(string)
+
+				var variable struct {
+					Value string `document:"value"`
+				}
+				if err := client.RestAPIGet(ctx, mwaaEnvName, variablePath(key), nil, &variable); err != nil {
+					return fmt.Errorf("failed to get variable %s: %w", key, err)
+				}
+
+				values[key] = variable.Value
+			}
+
+			data, err := formatVariablesFile(values)
+			if err != nil {
+				return err
+			}
+
+			if len(args) == 0 {
+				cmd.Print(string(data))
+				return nil
+			}
+
+			if err := os.WriteFile(args[0], data, 0600); err != nil {
+				return fmt.Errorf("failed to write file %s: %w", args[0], err)
+			}
+
+			cmd.Println(green("[SUCCESS]"), fmt.Sprintf("%d variables exported to %s.", len(values), args[0]))
+
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&mwaaEnvName, "env", "", "MWAA environment name")
+
+	return cmd
+}
+
+// setVariable updates a variable, creating it if it does not exist yet.
+// The description is left untouched when it is nil. It reports whether the variable was created.
+func setVariable(ctx context.Context, client *mwaa.Client, mwaaEnvName, key, value string, description *string) (map[string]any, bool, error) {
+	payload := map[string]any{
+		"key":   key,
+		"value": value,
+	}
+
+	updateMask := []string{"value"}
+
+	if description != nil {
+		payload["description"] = *description
+		updateMask = append(updateMask, "description")
+	}
+
+	queryParams := map[string]any{
+		"update_mask": updateMask,
+	}
+
+	var response map[string]any
+
+	err := client.RestAPIPatch(ctx, mwaaEnvName, variablePath(key), queryParams, payload, &response)
+	if err == nil {
+		return response, false, nil
+	}
+
+	if !mwaa.IsNotFound(err) {
+		return nil, false, err
+	}
+
+	if err := client.RestAPIPost(ctx, mwaaEnvName, "/variables", nil, payload, &response); err != nil {
+		return nil, false, err
+	}
+
+	return response, true, nil
+}
+
+// variableImportPlan describes the changes an import applies, grouped by kind.
+type variableImportPlan struct {
+	create    []string
+	update    []string
+	skip      []string
+	unchanged []string
+}
+
+// planVariableImport compares the desired variables with the existing ones and decides what to change.
+// Existing variables with a different value are an error unless overwrite or skipExisting is set.
+func planVariableImport(desired, existing map[string]string, overwrite, skipExisting bool) (*variableImportPlan, error) {
+	plan := &variableImportPlan{}
+
+	var conflicts []string
+
+	for key, value := range desired {
+		current, exists := existing[key]
+
+		switch {
+		case !exists:
+			plan.create = append(plan.create, key)
+		case variableValuesEqual(current, value):
+			plan.unchanged = append(plan.unchanged, key)
+		case overwrite:
+			plan.update = append(plan.update, key)
+		case skipExisting:
+			plan.skip = append(plan.skip, key)
+		default:
+			conflicts = append(conflicts, key)
+		}
+	}
+
+	if len(conflicts) > 0 {
+		sort.Strings(conflicts)
+		return nil, fmt.Errorf("variables already exist with a different value: %s (use --overwrite or --skip-existing)", strings.Join(conflicts, ", "))
+	}
+
+	sort.Strings(plan.create)
+	sort.Strings(plan.update)
+	sort.Strings(plan.skip)
+	sort.Strings(plan.unchanged)
+
+	return plan, nil
+}
+
+// parseVariablesFile parses a variables file in the format used by "airflow variables export".
+// String values are kept as they are, all other JSON values keep their original serialization.
+func parseVariablesFile(data []byte) (map[string]string, error) {
+	var raw map[string]json.RawMessage
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, fmt.Errorf("failed to parse variables file: %w", err)
+	}
+
+	variables := make(map[string]string, len(raw))
+
+	for key, value := range raw {
+		var str string
+		if err := json.Unmarshal(value, &str); err == nil {
+			variables[key] = str
+			continue
+		}
+
+		variables[key] = string(bytes.TrimSpace(value))
+	}
+
+	return variables, nil
+}
+
+// variableValuesEqual reports whether two variable values are equal.
+// Values holding valid JSON are compared semantically, so formatting and key order are ignored.
+func variableValuesEqual(a, b string) bool {
+	if a == b {
+		return true
+	}
+
+	decode := func(value string) (any, bool) {
+		decoder := json.NewDecoder(strings.NewReader(value))
+		decoder.UseNumber()
+
+		var v any
+		if err := decoder.Decode(&v); err != nil || decoder.More() {
+			return nil, false
+		}
+
+		return v, true
+	}
+
+	decodedA, okA := decode(a)
+	decodedB, okB := decode(b)
+
+	return okA && okB && reflect.DeepEqual(decodedA, decodedB)
+}
+
+// formatVariablesFile renders variables in the format used by "airflow variables export".
+// Values holding valid JSON are written as JSON, all other values as strings.
+func formatVariablesFile(variables map[string]string) ([]byte, error) {
+	raw := make(map[string]any, len(variables))
+
+	for key, value := range variables {
+		raw[key] = value
+
+		if json.Valid([]byte(value)) {
+			raw[key] = json.RawMessage(value)
+		}
+	}
+
+	var buf bytes.Buffer
+
+	encoder := json.NewEncoder(&buf)
+	encoder.SetEscapeHTML(false)
+	encoder.SetIndent("", "    ")
+
+	if err := encoder.Encode(raw); err != nil {
+		return nil, fmt.Errorf("failed to serialize variables: %w", err)
+	}
+
+	return buf.Bytes(), nil
+}
+
+// variablePath returns the REST API path of a variable.
+func variablePath(key string) string {
+	return fmt.Sprintf("/variables/%s", url.PathEscape(key))
+}
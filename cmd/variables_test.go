@@ -0,0 +1,142 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPlanVariableImport(t *testing.T) {
+	desired := map[string]string{
+		"new":       "1",
+		"same":      "2",
+		"different": "3",
+	}
+
+	existing := map[string]string{
+		"same":      "2",
+		"different": "old",
+	}
+
+	tests := []struct {
+		name         string
+		overwrite    bool
+		skipExisting bool
+		expected     *variableImportPlan
+		expectError  bool
+	}{
+		{
+			name:        "Conflict without flags",
+			expectError: true,
+		},
+		{
+			name:      "Overwrite existing",
+			overwrite: true,
+			expected: &variableImportPlan{
+				create:    []string{"new"},
+				update:    []string{"different"},
+				unchanged: []string{"same"},
+			},
+		},
+		{
+			name:         "Skip existing",
+			skipExisting: true,
+			expected: &variableImportPlan{
+				create:    []string{"new"},
+				skip:      []string{"different"},
+				unchanged: []string{"same"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			plan, err := planVariableImport(desired, existing, tt.overwrite, tt.skipExisting)
+
+			if tt.expectError {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.expected, plan)
+			}
+		})
+	}
+}
+
+func TestParseVariablesFile(t *testing.T) {
+	content := `{
+    "string": "value",
+    "number": 12345678901234567890,
+    "object": {"a": [1, 2]},
+    "bool": true
+}`
+
+	variables, err := parseVariablesFile([]byte(content))
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]string{
+		"string": "value",
+		"number": "12345678901234567890",
+		"object": `{"a": [1, 2]}`,
+		"bool":   "true",
+	}, variables)
+
+	_, err = parseVariablesFile([]byte("not json"))
+	assert.Error(t, err)
+}
+
+func TestFormatVariablesFile(t *testing.T) {
+	variables := map[string]string{
+		"string": "value",
+		"object": `{"a":[1,2]}`,
+	}
+
+	data, err := formatVariablesFile(variables)
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"string": "value", "object": {"a": [1, 2]}}`, string(data))
+
+	roundTrip, err := parseVariablesFile(data)
+	assert.NoError(t, err)
+
+	for key, value := range variables {
+		assert.True(t, variableValuesEqual(value, roundTrip[key]), key)
+	}
+}
+
+func TestParseVariablesFileKeepsJSONValues(t *testing.T) {
+	// Airflow stores non-string values as json.dumps output.
+	stored := `{"url": "a&b<c", "z": 1, "a": 2}`
+
+	variables, err := parseVariablesFile([]byte(`{"config": ` + stored + `}`))
+	assert.NoError(t, err)
+	assert.Equal(t, stored, variables["config"])
+
+	plan, err := planVariableImport(variables, map[string]string{"config": stored}, false, false)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"config"}, plan.unchanged)
+
+	data, err := formatVariablesFile(variables)
+	assert.NoError(t, err)
+	assert.Contains(t, string(data), "a&b<c")
+}
+
+func TestVariableValuesEqual(t *testing.T) {
+	tests := []struct {
+		name     string
+		a        string
+		b        string
+		expected bool
+	}{
+		{name: "Identical strings", a: "value", b: "value", expected: true},
+		{name: "Different strings", a: "value", b: "other", expected: false},
+		{name: "Reformatted JSON", a: `{"a": 2, "z": [1, 2]}`, b: `{"z":[1,2],"a":2}`, expected: true},
+		{name: "Different JSON", a: `{"a": 2}`, b: `{"a": 3}`, expected: false},
+		{name: "Large numbers", a: `12345678901234567890`, b: `12345678901234567891`, expected: false},
+		{name: "JSON and plain string", a: `{"a": 2}`, b: "a: 2", expected: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, variableValuesEqual(tt.a, tt.b))
+		})
+	}
+}
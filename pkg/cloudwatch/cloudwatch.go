@@ -1,13 +1,15 @@
 // Package cloudwatch provides a client for interacting with Amazon CloudWatch Logs.
 // It simplifies fetching and filtering log events from CloudWatch log groups, enabling
-// efficient log retrieval and processing.
+// efficient log retrieval and processing by streaming the events of several log groups in timestamp order.
 package cloudwatch
 
 import (
+	"container/heap"
 	"context"
 	"fmt"
-	"sort"
 	"strings"
+	"sync"
+	"time"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/service/cloudwatchlogs"
@@ -16,9 +18,11 @@ import (
 
 // LogEvent represents a CloudWatch log event.
 type LogEvent struct {
+	EventID   string // The ID of the log event, unique within the log group.
 	Timestamp int64  // The timestamp of the log event in milliseconds since the epoch.
 	Message   string // The message content of the log event.
 	LogGroup  string // The name of the log group where the event was logged.
+	LogStream string // The name of the log stream where the event was logged.
 }
 
 // Client provides methods to interact with Amazon CloudWatch Logs.
@@ -40,64 +44,276 @@ type LogFilter struct {
 	FilterPattern *string // The filter pattern to match log events.
 }
 
-// FetchLogs retrieves log events from the specified CloudWatch log groups based on the provided filter.
-// This method fetches logs from multiple log groups, applies the filter, and sorts the logs by timestamp.
-func (c *Client) FetchLogs(ctx context.Context, logGroupARNs []string, filter *LogFilter) ([]LogEvent, error) {
-	var allLogs []LogEvent
+// logBufferSize is the number of log events buffered per log group while streaming.
+const logBufferSize = 1000
+
+// StreamLogs fetches log events from the specified CloudWatch log groups based on the provided filter and calls fn
+// for every event in timestamp order. The log groups are fetched concurrently and merged while they are read,
+// so the first events are passed to fn right away and memory use is bounded by a small buffer per log group.
+func (c *Client) StreamLogs(ctx context.Context, logGroupARNs []string, filter *LogFilter, fn func(LogEvent) error) error {
+	producers := make([]logProducer, 0, len(logGroupARNs))
 
 	for _, arn := range logGroupARNs {
 		logGroupName, err := extractLogGroupName(arn)
 		if err != nil {
-			return nil, fmt.Errorf("failed to extract log group name: %w", err)
+			return fmt.Errorf("failed to extract log group name: %w", err)
 		}
 
-		logs, err := c.getFilteredLogs(ctx, logGroupName, filter)
+		producers = append(producers, c.logGroupProducer(logGroupName, filter))
+	}
+
+	return mergeLogs(ctx, producers, fn)
+}
+
+// FollowOptions defines how FollowLogs polls for new log events.
+type FollowOptions struct {
+	PollInterval time.Duration // The interval between two polls.
+	Lookback     time.Duration // How far before the newest seen event each poll starts, to catch late-arriving events.
+}
+
+// FollowLogs polls the specified CloudWatch log groups for new log events until the context is canceled,
+// calling fn for every new event. Log streams are ingested independently, so an event may arrive after newer
+// events of another stream. Each poll therefore starts the lookback before the newest seen event of a log group,
+// and the events seen within that window are de-duplicated by their event ID. Events that arrive later than
+// the lookback are missed. The events of a poll are passed to fn in timestamp order, late events may be older
+// than events of a previous poll. The EndTime of the filter is ignored.
+func (c *Client) FollowLogs(ctx context.Context, logGroupARNs []string, filter *LogFilter, opts *FollowOptions, fn func(LogEvent) error) error {
+	logGroupNames := make([]string, 0, len(logGroupARNs))
+	followers := make(map[string]*logFollower, len(logGroupARNs))
+
+	for _, arn := range logGroupARNs {
+		logGroupName, err := extractLogGroupName(arn)
 		if err != nil {
-			return nil, fmt.Errorf("failed to fetch logs for %s: %w", arn, err)
+			return fmt.Errorf("failed to extract log group name: %w", err)
 		}
 
-		allLogs = append(allLogs, logs...)
+		logGroupNames = append(logGroupNames, logGroupName)
+		followers[logGroupName] = newLogFollower(aws.ToInt64(filter.StartTime), opts.Lookback)
 	}
 
-	// Sort logs by timestamp
-	sort.Slice(allLogs, func(i, j int) bool {
-		return allLogs[i].Timestamp < allLogs[j].Timestamp
-	})
+	for {
+		// The producers are created in a fixed order, which breaks ties between events with equal timestamps
+		producers := make([]logProducer, 0, len(logGroupNames))
+		for _, logGroupName := range logGroupNames {
+			producers = append(producers, c.logGroupProducer(logGroupName, &LogFilter{
+				StartTime:     aws.Int64(followers[logGroupName].nextStartTime()),
+				FilterPattern: filter.FilterPattern,
+			}))
+		}
+
+		err := mergeLogs(ctx, producers, func(log LogEvent) error {
+			if !followers[log.LogGroup].observe(log) {
+				return nil
+			}
+
+			return fn(log)
+		})
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+
+			return err
+		}
+
+		for _, f := range followers {
+			f.prune()
+		}
 
-	return allLogs, nil
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(opts.PollInterval):
+		}
+	}
 }
 
-// getFilteredLogs retrieves filtered log events from a specific CloudWatch log group.
-// This method uses a paginator to fetch all pages of log events that match the filter criteria.
-func (c *Client) getFilteredLogs(ctx context.Context, logGroupName string, filter *LogFilter) ([]LogEvent, error) {
-	var logs []LogEvent
+// logProducer sends the log events of a single source to out in timestamp order.
+type logProducer func(ctx context.Context, out chan<- LogEvent) error
 
-	// Create a paginator for the FilterLogEvents API
-	paginator := cloudwatchlogs.NewFilterLogEventsPaginator(c.client, &cloudwatchlogs.FilterLogEventsInput{
-		LogGroupName:  aws.String(logGroupName),
-		StartTime:     filter.StartTime,
-		EndTime:       filter.EndTime,
-		FilterPattern: filter.FilterPattern,
-	})
+// logGroupProducer returns a logProducer that pages through the filtered log events of a CloudWatch log group.
+// It relies on FilterLogEvents returning the events of a log group in timestamp order.
+func (c *Client) logGroupProducer(logGroupName string, filter *LogFilter) logProducer {
+	return func(ctx context.Context, out chan<- LogEvent) error {
+		// Create a paginator for the FilterLogEvents API
+		paginator := cloudwatchlogs.NewFilterLogEventsPaginator(c.client, &cloudwatchlogs.FilterLogEventsInput{
+			LogGroupName:  aws.String(logGroupName),
+			StartTime:     filter.StartTime,
+			EndTime:       filter.EndTime,
+			FilterPattern: filter.FilterPattern,
+		})
 
-	// Iterate through all pages
-	for paginator.HasMorePages() {
-		resp, err := paginator.NextPage(ctx)
-		if err != nil {
-			return nil, fmt.Errorf("failed to get log events: %w", err)
+		for paginator.HasMorePages() {
+			resp, err := paginator.NextPage(ctx)
+			if err != nil {
+				return fmt.Errorf("failed to fetch logs for %s: %w", logGroupName, err)
+			}
+
+			for _, event := range resp.Events {
+				log := LogEvent{
+					EventID:   aws.ToString(event.EventId),
+					Timestamp: aws.ToInt64(event.Timestamp),
+					Message:   aws.ToString(event.Message),
+					LogGroup:  logGroupName,
+					LogStream: aws.ToString(event.LogStreamName),
+				}
+
+				select {
+				case out <- log:
+				case <-ctx.Done():
+					return ctx.Err()
+				}
+			}
+		}
+
+		return nil
+	}
+}
+
+// logSource is the output of a running logProducer.
+type logSource struct {
+	index  int           // The position of the producer, used to order events with equal timestamps.
+	events chan LogEvent // Closed when the producer returns.
+	err    error         // The error returned by the producer, set before events is closed.
+}
+
+// mergeLogs runs the producers concurrently and calls fn for every event in timestamp order,
+// using a k-way merge over the heads of the producers. The first error of a producer or of fn
+// stops all producers and is returned.
+func mergeLogs(ctx context.Context, producers []logProducer, fn func(LogEvent) error) error {
+	var wg sync.WaitGroup
+	defer wg.Wait()
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	h := &logHeap{}
+
+	// next pushes the next event of the source onto the heap, if there is one.
+	next := func(src *logSource) error {
+		log, ok := <-src.events
+		if !ok {
+			return src.err
+		}
+
+		heap.Push(h, logHeapItem{log: log, source: src})
+
+		return nil
+	}
+
+	sources := make([]*logSource, 0, len(producers))
+
+	for i, produce := range producers {
+		src := &logSource{index: i, events: make(chan LogEvent, logBufferSize)}
+		sources = append(sources, src)
+
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+			defer close(src.events)
+
+			src.err = produce(ctx, src.events)
+		}()
+	}
+
+	for _, src := range sources {
+		if err := next(src); err != nil {
+			return err
 		}
+	}
+
+	for h.Len() > 0 {
+		item := heap.Pop(h).(logHeapItem)
 
-		// Append log events from the current page
-		for _, event := range resp.Events {
-			logs = append(logs, LogEvent{
-				Timestamp: *event.Timestamp,
-				Message:   *event.Message,
-				LogGroup:  logGroupName,
-			})
+		if err := fn(item.log); err != nil {
+			return err
 		}
+
+		if err := next(item.source); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// logHeapItem is the head event of a log source.
+type logHeapItem struct {
+	log    LogEvent
+	source *logSource
+}
+
+// logHeap is a min-heap of log source heads ordered by timestamp.
+type logHeap []logHeapItem
+
+func (h logHeap) Len() int { return len(h) }
+
+func (h logHeap) Less(i, j int) bool {
+	if h[i].log.Timestamp != h[j].log.Timestamp {
+		return h[i].log.Timestamp < h[j].log.Timestamp
 	}
 
-	return logs, nil
+	return h[i].source.index < h[j].source.index
+}
+
+func (h logHeap) Swap(i, j int) { h[i], h[j] = h[j], h[i] }
+
+func (h *logHeap) Push(x any) { *h = append(*h, x.(logHeapItem)) }
+
+func (h *logHeap) Pop() any {
+	old := *h
+	item := old[len(old)-1]
+	*h = old[:len(old)-1]
+
+	return item
+}
+
+// logFollower tracks the position of FollowLogs in a single log group.
+type logFollower struct {
+	startTime int64            // The start time requested by the user in milliseconds since the epoch.
+	lookback  int64            // The lookback of every poll in milliseconds.
+	latest    int64            // The newest timestamp seen.
+	seen      map[string]int64 // The timestamps of the seen events by event ID.
+}
+
+// newLogFollower creates a logFollower that starts at the given time.
+func newLogFollower(startTime int64, lookback time.Duration) *logFollower {
+	return &logFollower{
+		startTime: startTime,
+		lookback:  lookback.Milliseconds(),
+		latest:    startTime,
+		seen:      map[string]int64{},
+	}
+}
+
+// nextStartTime returns the start time of the next poll, which is the lookback before the newest seen event,
+// but never before the requested start time.
+func (f *logFollower) nextStartTime() int64 {
+	return max(f.startTime, f.latest-f.lookback)
+}
+
+// observe records an event of a poll and reports whether it was not seen before.
+func (f *logFollower) observe(log LogEvent) bool {
+	if _, ok := f.seen[log.EventID]; ok {
+		return false
+	}
+
+	f.seen[log.EventID] = log.Timestamp
+	f.latest = max(f.latest, log.Timestamp)
+
+	return true
+}
+
+// prune forgets the events before the start time of the next poll, which cannot be returned again.
+func (f *logFollower) prune() {
+	startTime := f.nextStartTime()
+
+	for id, timestamp := range f.seen {
+		if timestamp < startTime {
+			delete(f.seen, id)
+		}
+	}
 }
 
 // extractLogGroupName extracts the log group name from a CloudWatch log group ARN.
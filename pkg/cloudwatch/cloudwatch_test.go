@@ -0,0 +1,152 @@
+package cloudwatch
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/cloudwatchlogs/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExtractLogGroupName(t *testing.T) {
+	name, err := extractLogGroupName("arn:aws:logs:eu-west-1:123456789012:log-group:airflow-analytics-Scheduler")
+	assert.NoError(t, err)
+	assert.Equal(t, "airflow-analytics-Scheduler", name)
+
+	_, err = extractLogGroupName("airflow-analytics-Scheduler")
+	assert.Error(t, err)
+}
+
+func TestLogFollower(t *testing.T) {
+	f := newLogFollower(100, 0)
+
+	for _, log := range []LogEvent{{EventID: "a", Timestamp: 100}, {EventID: "b", Timestamp: 200}, {EventID: "c", Timestamp: 200}} {
+		assert.True(t, f.observe(log))
+	}
+
+	f.prune()
+	assert.Equal(t, int64(200), f.nextStartTime())
+
+	// The next poll starts at the last seen timestamp and returns its events again
+	assert.False(t, f.observe(LogEvent{EventID: "b", Timestamp: 200}))
+	assert.False(t, f.observe(LogEvent{EventID: "c", Timestamp: 200}))
+	assert.True(t, f.observe(LogEvent{EventID: "d", Timestamp: 200}))
+	assert.True(t, f.observe(LogEvent{EventID: "e", Timestamp: 300}))
+
+	f.prune()
+	assert.Equal(t, int64(300), f.nextStartTime())
+
+	// Events before the start time of the next poll are forgotten
+	assert.Equal(t, map[string]int64{"e": 300}, f.seen)
+}
+
+func TestLogFollowerLateEvent(t *testing.T) {
+	f := newLogFollower(1_000, 30*time.Second)
+
+	// The start time requested by the user is never undercut
+	assert.Equal(t, int64(1_000), f.nextStartTime())
+
+	assert.True(t, f.observe(LogEvent{EventID: "worker-1", Timestamp: 50_000}))
+	assert.True(t, f.observe(LogEvent{EventID: "worker-2", Timestamp: 60_000}))
+
+	f.prune()
+	assert.Equal(t, int64(30_000), f.nextStartTime())
+
+	// The next poll re-reads the lookback window, returning the seen events again and an event of another
+	// log stream that was ingested late, with a timestamp before the newest seen event
+	assert.False(t, f.observe(LogEvent{EventID: "worker-1", Timestamp: 50_000}))
+	assert.True(t, f.observe(LogEvent{EventID: "scheduler-1", Timestamp: 55_000}))
+	assert.False(t, f.observe(LogEvent{EventID: "worker-2", Timestamp: 60_000}))
+	assert.True(t, f.observe(LogEvent{EventID: "worker-3", Timestamp: 85_000}))
+
+	f.prune()
+	assert.Equal(t, int64(55_000), f.nextStartTime())
+
+	// Only the events before the lookback window are forgotten
+	assert.Equal(t, map[string]int64{"scheduler-1": 55_000, "worker-2": 60_000, "worker-3": 85_000}, f.seen)
+}
+
+// sliceProducer returns a logProducer that sends the given events.
+func sliceProducer(logs ...LogEvent) logProducer {
+	return func(ctx context.Context, out chan<- LogEvent) error {
+		for _, log := range logs {
+			select {
+			case out <- log:
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		}
+
+		return nil
+	}
+}
+
+func TestMergeLogs(t *testing.T) {
+	producers := []logProducer{
+		sliceProducer(LogEvent{EventID: "a1", Timestamp: 1}, LogEvent{EventID: "a4", Timestamp: 4}, LogEvent{EventID: "a5", Timestamp: 5}),
+		sliceProducer(),
+		sliceProducer(LogEvent{EventID: "b2", Timestamp: 2}, LogEvent{EventID: "b4", Timestamp: 4}, LogEvent{EventID: "b6", Timestamp: 6}),
+		sliceProducer(LogEvent{EventID: "c3", Timestamp: 3}),
+	}
+
+	var ids []string
+
+	err := mergeLogs(context.Background(), producers, func(log LogEvent) error {
+		ids = append(ids, log.EventID)
+		return nil
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"a1", "b2", "c3", "a4", "b4", "a5", "b6"}, ids)
+}
+
+func TestMergeLogsErrors(t *testing.T) {
+	t.Run("Producer error", func(t *testing.T) {
+		failing := func(_ context.Context, _ chan<- LogEvent) error {
+			return errors.New("access denied")
+		}
+
+		err := mergeLogs(context.Background(), []logProducer{sliceProducer(LogEvent{Timestamp: 1}), failing}, func(LogEvent) error {
+			return nil
+		})
+		assert.EqualError(t, err, "access denied")
+	})
+
+	t.Run("Callback error stops the producers", func(t *testing.T) {
+		logs := make([]LogEvent, 2*logBufferSize)
+		for i := range logs {
+			logs[i] = LogEvent{Timestamp: int64(i)}
+		}
+
+		calls := 0
+
+		err := mergeLogs(context.Background(), []logProducer{sliceProducer(logs...)}, func(LogEvent) error {
+			calls++
+			return errors.New("broken pipe")
+		})
+		assert.EqualError(t, err, "broken pipe")
+		assert.Equal(t, 1, calls)
+	})
+}
+
+func TestNewQueryResults(t *testing.T) {
+	results := newQueryResults([][]types.ResultField{
+		{
+			{Field: aws.String("dag_id"), Value: aws.String("etl")},
+			{Field: aws.String("failures"), Value: aws.String("3")},
+			{Field: aws.String("@ptr"), Value: aws.String("CmAKJwoj")},
+		},
+		{
+			{Field: aws.String("dag_id"), Value: aws.String("reporting")},
+			{Field: aws.String("task_id"), Value: aws.String("load")},
+		},
+	})
+
+	assert.Equal(t, []string{"dag_id", "failures", "task_id"}, results.Fields)
+	assert.Equal(t, []map[string]string{
+		{"dag_id": "etl", "failures": "3"},
+		{"dag_id": "reporting", "task_id": "load"},
+	}, results.Rows)
+}
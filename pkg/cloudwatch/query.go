@@ -0,0 +1,126 @@
+package cloudwatch
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/cloudwatchlogs"
+	"github.com/aws/aws-sdk-go-v2/service/cloudwatchlogs/types"
+)
+
+// QueryInput defines a CloudWatch Logs Insights query.
+type QueryInput struct {
+	QueryString  string        // The Logs Insights query.
+	StartTime    int64         // The start of the queried time range in milliseconds since the epoch.
+	EndTime      int64         // The end of the queried time range in milliseconds since the epoch.
+	Limit        *int32        // Optional maximum number of rows to return.
+	PollInterval time.Duration // The interval between checks for the query results, defaults to one second.
+}
+
+// QueryResults holds the rows returned by a CloudWatch Logs Insights query.
+type QueryResults struct {
+	Fields []string            // The field names in the order they first appear in the rows.
+	Rows   []map[string]string // The rows, mapping field names to values.
+}
+
+// RunQuery runs a CloudWatch Logs Insights query against the specified log groups and waits for its results.
+// The query is stopped if RunQuery returns before it ends, e.g. because the context is canceled.
+func (c *Client) RunQuery(ctx context.Context, logGroupARNs []string, input *QueryInput) (*QueryResults, error) {
+	logGroupNames := make([]string, 0, len(logGroupARNs))
+
+	for _, arn := range logGroupARNs {
+		logGroupName, err := extractLogGroupName(arn)
+		if err != nil {
+			return nil, fmt.Errorf("failed to extract log group name: %w", err)
+		}
+
+		logGroupNames = append(logGroupNames, logGroupName)
+	}
+
+	pollInterval := input.PollInterval
+	if pollInterval <= 0 {
+		pollInterval = time.Second
+	}
+
+	// Logs Insights uses seconds since the epoch
+	started, err := c.client.StartQuery(ctx, &cloudwatchlogs.StartQueryInput{
+		LogGroupNames: logGroupNames,
+		QueryString:   aws.String(input.QueryString),
+		StartTime:     aws.Int64(input.StartTime / 1000),
+		EndTime:       aws.Int64(input.EndTime / 1000),
+		Limit:         input.Limit,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to start query: %w", err)
+	}
+
+	finished := false
+
+	defer func() {
+		// The query keeps running and scanning data unless it is stopped, so stop it on every early return,
+		// independently of a canceled context.
+		if !finished {
+			_, _ = c.client.StopQuery(context.Background(), &cloudwatchlogs.StopQueryInput{QueryId: started.QueryId})
+		}
+	}()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(pollInterval):
+		}
+
+		output, err := c.client.GetQueryResults(ctx, &cloudwatchlogs.GetQueryResultsInput{QueryId: started.QueryId})
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil, ctx.Err()
+			}
+
+			return nil, fmt.Errorf("failed to get query results: %w", err)
+		}
+
+		switch output.Status {
+		case types.QueryStatusComplete:
+			finished = true
+			return newQueryResults(output.Results), nil
+		case types.QueryStatusFailed, types.QueryStatusCancelled, types.QueryStatusTimeout, types.QueryStatusUnknown:
+			finished = true
+			return nil, fmt.Errorf("query %s ended with status %s", aws.ToString(started.QueryId), output.Status)
+		case types.QueryStatusScheduled, types.QueryStatusRunning:
+		}
+	}
+}
+
+// newQueryResults converts the raw result rows of a query. The internal @ptr field is dropped.
+func newQueryResults(results [][]types.ResultField) *QueryResults {
+	queryResults := &QueryResults{
+		Rows: make([]map[string]string, 0, len(results)),
+	}
+
+	known := map[string]bool{}
+
+	for _, result := range results {
+		row := make(map[string]string, len(result))
+
+		for _, field := range result {
+			name := aws.ToString(field.Field)
+			if name == "@ptr" {
+				continue
+			}
+
+			if !known[name] {
+				known[name] = true
+				queryResults.Fields = append(queryResults.Fields, name)
+			}
+
+			row[name] = aws.ToString(field.Value)
+		}
+
+		queryResults.Rows = append(queryResults.Rows, row)
+	}
+
+	return queryResults
+}
@@ -0,0 +1,108 @@
+package local
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/hupe1980/mwaacli/pkg/util"
+)
+
+// ValidatePluginsDir checks that a plugins directory has the layout MWAA expects for plugins.zip:
+// the plugin files must be at the root of the archive, not wrapped in a single top-level folder,
+// and compiled Python files must not be included.
+func ValidatePluginsDir(dir string) error {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return fmt.Errorf("failed to read plugins directory %s: %w", dir, err)
+	}
+
+	if len(entries) == 0 {
+		return fmt.Errorf("plugins directory %s is empty", dir)
+	}
+
+	var errs []error
+
+	if len(entries) == 1 && entries[0].IsDir() {
+		errs = append(errs, fmt.Errorf("plugins directory %s only contains the folder %s; the plugin files must be at the root of plugins.zip", dir, entries[0].Name()))
+	}
+
+	err = filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		relPath, err := filepath.Rel(dir, path)
+		if err != nil {
+			return err
+		}
+
+		switch {
+		case d.IsDir() && d.Name() == "__pycache__":
+			errs = append(errs, fmt.Errorf("%s must not be included in plugins.zip", filepath.ToSlash(relPath)))
+			return filepath.SkipDir
+		case !d.IsDir() && strings.HasSuffix(d.Name(), ".pyc"):
+			errs = append(errs, fmt.Errorf("%s must not be included in plugins.zip", filepath.ToSlash(relPath)))
+		}
+
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to read plugins directory %s: %w", dir, err)
+	}
+
+	return errors.Join(errs...)
+}
+
+// PackagePlugins validates the plugins directory and writes it as a zip archive to outputPath.
+// The archive is written to a temporary file first, so outputPath is left untouched if packaging fails.
+// It returns the hex-encoded SHA-256 checksum of the archive.
+func PackagePlugins(dir, outputPath string) (string, error) {
+	if err := ValidatePluginsDir(dir); err != nil {
+		return "", err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(outputPath), 0755); err != nil {
+		return "", fmt.Errorf("failed to create directory for %s: %w", outputPath, err)
+	}
+
+	file, err := os.CreateTemp(filepath.Dir(outputPath), "."+filepath.Base(outputPath)+"-*")
+	if err != nil {
+		return "", fmt.Errorf("failed to create %s: %w", outputPath, err)
+	}
+
+	tempPath := file.Name()
+
+	defer func() {
+		// Closing and removing are no-ops once the file has been renamed
+		_ = file.Close()
+		_ = os.Remove(tempPath)
+	}()
+
+	hash := sha256.New()
+
+	if err := util.ZipDirectory(dir, io.MultiWriter(file, hash)); err != nil {
+		return "", fmt.Errorf("failed to package plugins: %w", err)
+	}
+
+	if err := file.Close(); err != nil {
+		return "", fmt.Errorf("failed to write %s: %w", outputPath, err)
+	}
+
+	// CreateTemp creates the file with mode 0600
+	if err := os.Chmod(tempPath, 0644); err != nil { //nolint:gosec // plugins.zip is not secret and is uploaded to S3
+		return "", fmt.Errorf("failed to write %s: %w", outputPath, err)
+	}
+
+	if err := os.Rename(tempPath, outputPath); err != nil {
+		return "", fmt.Errorf("failed to write %s: %w", outputPath, err)
+	}
+
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
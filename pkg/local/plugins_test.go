@@ -0,0 +1,114 @@
+package local
+
+import (
+	"archive/zip"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidatePluginsDir(t *testing.T) {
+	tests := []struct {
+		name        string
+		files       []string
+		expectError string
+	}{
+		{
+			name:  "Valid layout",
+			files: []string{"__init__.py", "operators/__init__.py", "operators/custom.py"},
+		},
+		{
+			name:        "Nested top-level folder",
+			files:       []string{"plugins/__init__.py", "plugins/custom.py"},
+			expectError: "only contains the folder plugins",
+		},
+		{
+			name:        "Compiled Python files",
+			files:       []string{"__init__.py", "operators/__pycache__/custom.cpython-311.pyc", "legacy.pyc"},
+			expectError: "operators/__pycache__ must not be included",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+
+			for _, file := range tt.files {
+				path := filepath.Join(dir, filepath.FromSlash(file))
+				assert.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+				assert.NoError(t, os.WriteFile(path, []byte("# plugin\n"), 0600))
+			}
+
+			err := ValidatePluginsDir(dir)
+			if tt.expectError == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.ErrorContains(t, err, tt.expectError)
+			}
+		})
+	}
+
+	assert.Error(t, ValidatePluginsDir(t.TempDir()))
+}
+
+func TestPackagePlugins(t *testing.T) {
+	dir := t.TempDir()
+	assert.NoError(t, os.MkdirAll(filepath.Join(dir, "operators"), 0755))
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "operators", "custom.py"), []byte("# operator\n"), 0600))
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "custom.py"), []byte("# plugin\n"), 0600))
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "__init__.py"), []byte(""), 0600))
+
+	output := filepath.Join(t.TempDir(), "plugins.zip")
+
+	first, err := PackagePlugins(dir, output)
+	assert.NoError(t, err)
+	assert.Len(t, first, 64)
+
+	firstData, err := os.ReadFile(output)
+	assert.NoError(t, err)
+
+	// The same content with other modification times and permissions must produce the same archive
+	modTime := time.Now().Add(-time.Hour)
+	for _, file := range []string{"__init__.py", "custom.py", "operators/custom.py", "operators"} {
+		assert.NoError(t, os.Chtimes(filepath.Join(dir, filepath.FromSlash(file)), modTime, modTime))
+	}
+
+	assert.NoError(t, os.Chmod(filepath.Join(dir, "custom.py"), 0640))
+
+	second, err := PackagePlugins(dir, output)
+	assert.NoError(t, err)
+	assert.Equal(t, first, second)
+
+	secondData, err := os.ReadFile(output)
+	assert.NoError(t, err)
+	assert.Equal(t, firstData, secondData)
+
+	reader, err := zip.OpenReader(output)
+	assert.NoError(t, err)
+	defer reader.Close()
+
+	names := make([]string, 0, len(reader.File))
+	for _, file := range reader.File {
+		names = append(names, file.Name)
+	}
+
+	assert.Equal(t, []string{"__init__.py", "custom.py", "operators/custom.py"}, names)
+}
+
+func TestPackagePluginsFailure(t *testing.T) {
+	dir := t.TempDir()
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "__init__.py"), []byte(""), 0600))
+	assert.NoError(t, os.Symlink(filepath.Join(dir, "__init__.py"), filepath.Join(dir, "link.py")))
+
+	outputDir := t.TempDir()
+
+	_, err := PackagePlugins(dir, filepath.Join(outputDir, "plugins.zip"))
+	assert.ErrorContains(t, err, "unsupported file type")
+
+	entries, err := os.ReadDir(outputDir)
+	assert.NoError(t, err)
+	assert.Empty(t, entries)
+}
@@ -1,12 +1,27 @@
 package local
 
 import (
+	"archive/zip"
+	"bytes"
 	"context"
+	"crypto/md5"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path"
 	"path/filepath"
+	"sort"
+	"strings"
+	"unicode/utf8"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/hupe1980/mwaacli/pkg/config"
 	"github.com/hupe1980/mwaacli/pkg/s3"
+	"github.com/hupe1980/mwaacli/pkg/util"
 )
 
 type Syncer struct {
@@ -52,3 +67,408 @@ func (s *Syncer) SyncStartupScript(ctx context.Context, input *SyncStartupScript
 		LocalPath: aws.String(localPath),
 	})
 }
+
+// LocalChange is a local file that differs from the last sync and would be lost by the next one.
+type LocalChange struct {
+	Path string // Slash-separated path relative to the synced directory
+	Kind string // "modified", "added" if the file only exists locally, or "deleted" if it was deleted locally
+	Diff string // Unified diff of the remote file against the local file, set for modified text files
+}
+
+// LocalChangesError is returned by a sync that would overwrite local changes without Force.
+type LocalChangesError struct {
+	Dir     string        // The local directory of the sync
+	Changes []LocalChange // The local changes sorted by path
+}
+
+// Error implements the error interface.
+func (e *LocalChangesError) Error() string {
+	return fmt.Sprintf("%d local changes in %s would be overwritten", len(e.Changes), e.Dir)
+}
+
+// syncManifestDir is the directory of the manifests that record the files written by the last sync.
+const syncManifestDir = DefaultClonePath + "/.sync"
+
+// compiledPythonGlobs match the files Python creates when the local runner imports plugins and DAGs.
+var compiledPythonGlobs = []string{"__pycache__", "*.pyc"}
+
+type SyncPluginsInput struct {
+	Bucket  *string // S3 bucket name
+	Key     *string // S3 object key (e.g., "plugins.zip")
+	Version *string // Optional S3 object version
+	Force   bool    // Overwrite local changes
+}
+
+// SyncPlugins replaces the local plugins directory with the content of the remote plugins.zip.
+// A LocalChangesError is returned if files changed since the last sync would be modified or deleted
+// and Force is not set. Compiled Python files are ignored.
+func (s *Syncer) SyncPlugins(ctx context.Context, input *SyncPluginsInput) error {
+	localDir := filepath.Join(DefaultClonePath, "plugins")
+	manifestPath := filepath.Join(syncManifestDir, "plugins.json")
+
+	data, err := s.s3Client.ReadObject(ctx, &s3.ReadObjectInput{
+		Bucket:  input.Bucket,
+		Key:     input.Key,
+		Version: input.Version,
+	})
+	if err != nil {
+		return err
+	}
+
+	filter, err := util.NewPathFilter(localDir, nil, compiledPythonGlobs, false)
+	if err != nil {
+		return err
+	}
+
+	if !input.Force {
+		reader, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+		if err != nil {
+			return fmt.Errorf("failed to create zip reader: %w", err)
+		}
+
+		remote, files, err := zipChecksums(reader, filter.Match)
+		if err != nil {
+			return err
+		}
+
+		changes, err := detectLocalChanges(localDir, manifestPath, filter.Match, remote, func(relPath string) ([]byte, error) {
+			return readZipFile(files[relPath])
+		})
+		if err != nil {
+			return err
+		}
+
+		if len(changes) > 0 {
+			return &LocalChangesError{Dir: localDir, Changes: changes}
+		}
+	}
+
+	if err := os.RemoveAll(localDir); err != nil {
+		return fmt.Errorf("failed to clean up %s: %w", localDir, err)
+	}
+
+	if err := os.MkdirAll(localDir, 0755); err != nil {
+		return fmt.Errorf("failed to create %s: %w", localDir, err)
+	}
+
+	if err := util.Unzip(data, localDir); err != nil {
+		return err
+	}
+
+	return writeSyncManifest(manifestPath, localDir, filter.Match)
+}
+
+type SyncDagsInput struct {
+	Bucket *string // S3 bucket name
+	Prefix *string // S3 prefix of the DAG folder (e.g., "dags")
+	Force  bool    // Overwrite local changes
+}
+
+// SyncDags makes the local DAG folder match the remote DAG folder. Compiled Python files are left untouched.
+// A LocalChangesError is returned if files changed since the last sync would be modified or deleted
+// and Force is not set.
+func (s *Syncer) SyncDags(ctx context.Context, input *SyncDagsInput) error {
+	localDir := filepath.Join(".", "dags")
+	manifestPath := filepath.Join(syncManifestDir, "dags.json")
+
+	if err := os.MkdirAll(localDir, 0755); err != nil {
+		return fmt.Errorf("failed to create %s: %w", localDir, err)
+	}
+
+	// Files listed in .airflowignore are synced as well, because DAGs may import them
+	filter, err := util.NewPathFilter(localDir, nil, compiledPythonGlobs, false)
+	if err != nil {
+		return err
+	}
+
+	if !input.Force {
+		remote, err := s.s3Client.ListChecksums(ctx, &s3.ListChecksumsInput{
+			Bucket: input.Bucket,
+			Prefix: input.Prefix,
+			Filter: filter.Match,
+		})
+		if err != nil {
+			return err
+		}
+
+		changes, err := detectLocalChanges(localDir, manifestPath, filter.Match, remote, func(relPath string) ([]byte, error) {
+			return s.s3Client.ReadObject(ctx, &s3.ReadObjectInput{
+				Bucket: input.Bucket,
+				Key:    aws.String(strings.TrimPrefix(path.Join(aws.ToString(input.Prefix), relPath), "/")),
+			})
+		})
+		if err != nil {
+			return err
+		}
+
+		if len(changes) > 0 {
+			return &LocalChangesError{Dir: localDir, Changes: changes}
+		}
+	}
+
+	if err := s.s3Client.SyncDirectory(ctx, &s3.SyncDirectoryInput{
+		Bucket:   input.Bucket,
+		Prefix:   input.Prefix,
+		LocalDir: aws.String(localDir),
+		Filter:   filter.Match,
+	}); err != nil {
+		return err
+	}
+
+	return writeSyncManifest(manifestPath, localDir, filter.Match)
+}
+
+// detectLocalChanges compares the local directory with the manifest of the last sync and returns the changes
+// the sync to the remote checksums would lose, with a diff for modified text files. The readRemote function
+// returns the content of a remote file.
+func detectLocalChanges(localDir, manifestPath string, filter func(relPath string) bool, remote map[string]string, readRemote func(relPath string) ([]byte, error)) ([]LocalChange, error) {
+	manifest, err := readSyncManifest(manifestPath)
+	if err != nil {
+		return nil, err
+	}
+
+	local, err := dirChecksums(localDir, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	changes := localChanges(manifest, local, remote)
+
+	for i, change := range changes {
+		if _, exists := remote[change.Path]; change.Kind != "modified" || !exists {
+			continue
+		}
+
+		remoteData, err := readRemote(change.Path)
+		if err != nil {
+			return nil, err
+		}
+
+		localData, err := os.ReadFile(filepath.Join(localDir, filepath.FromSlash(change.Path)))
+		if err != nil {
+			return nil, err
+		}
+
+		changes[i].Diff = textDiff(change.Path, remoteData, localData)
+	}
+
+	return changes, nil
+}
+
+// localChanges returns the local files that differ from the manifest of the last sync and would be lost by
+// syncing to the remote checksums. Files that already equal the remote files are never reported.
+// Without a manifest, i.e. before the first sync, every local file that differs from the remote file counts as a change.
+func localChanges(manifest, local, remote map[string]string) []LocalChange {
+	var changes []LocalChange
+
+	for relPath, checksum := range local {
+		remoteChecksum, inRemote := remote[relPath]
+		if inRemote && remoteChecksum == checksum {
+			continue
+		}
+
+		synced, inManifest := manifest[relPath]
+
+		switch {
+		case inManifest && synced == checksum:
+			// The file is unchanged since the last sync
+		case inManifest || inRemote:
+			changes = append(changes, LocalChange{Path: relPath, Kind: "modified"})
+		default:
+			changes = append(changes, LocalChange{Path: relPath, Kind: "added"})
+		}
+	}
+
+	for relPath := range manifest {
+		if _, inLocal := local[relPath]; inLocal {
+			continue
+		}
+
+		if _, inRemote := remote[relPath]; inRemote {
+			changes = append(changes, LocalChange{Path: relPath, Kind: "deleted"})
+		}
+	}
+
+	sort.Slice(changes, func(i, j int) bool {
+		return changes[i].Path < changes[j].Path
+	})
+
+	return changes
+}
+
+// textDiff returns a unified diff of the remote against the local content, or an empty string if one of them
+// is not text or they are too large to compare.
+func textDiff(relPath string, remote, local []byte) string {
+	if !isText(remote) || !isText(local) {
+		return ""
+	}
+
+	diff, err := util.UnifiedDiff("remote/"+relPath, "local/"+relPath, string(remote), string(local))
+	if err != nil {
+		return ""
+	}
+
+	return diff
+}
+
+// isText reports whether the data looks like UTF-8 encoded text.
+func isText(data []byte) bool {
+	return utf8.Valid(data) && !bytes.ContainsRune(data, 0)
+}
+
+// syncManifest records the files written by a sync.
+type syncManifest struct {
+	Files map[string]string `json:"files"` // The MD5 checksums by slash-separated relative path
+}
+
+// readSyncManifest returns the checksums recorded by the last sync, or nil if there was none.
+func readSyncManifest(manifestPath string) (map[string]string, error) {
+	data, err := os.ReadFile(manifestPath)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil, nil
+		}
+
+		return nil, fmt.Errorf("failed to read sync manifest: %w", err)
+	}
+
+	var manifest syncManifest
+	if err := json.Unmarshal(data, &manifest); err != nil {
+		return nil, fmt.Errorf("failed to parse sync manifest %s: %w", manifestPath, err)
+	}
+
+	if manifest.Files == nil {
+		manifest.Files = map[string]string{}
+	}
+
+	return manifest.Files, nil
+}
+
+// writeSyncManifest records the checksums of the files of the synced directory.
+func writeSyncManifest(manifestPath, localDir string, filter func(relPath string) bool) error {
+	files, err := dirChecksums(localDir, filter)
+	if err != nil {
+		return err
+	}
+
+	data, err := json.MarshalIndent(syncManifest{Files: files}, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(manifestPath), 0755); err != nil {
+		return fmt.Errorf("failed to create %s: %w", filepath.Dir(manifestPath), err)
+	}
+
+	if err := os.WriteFile(manifestPath, data, 0600); err != nil {
+		return fmt.Errorf("failed to write sync manifest: %w", err)
+	}
+
+	return nil
+}
+
+// dirChecksums returns the MD5 checksums of the files of the directory selected by the filter, keyed by
+// slash-separated relative path. A missing directory has no files.
+func dirChecksums(dir string, filter func(relPath string) bool) (map[string]string, error) {
+	checksums := map[string]string{}
+
+	err := filepath.WalkDir(dir, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			if errors.Is(err, fs.ErrNotExist) && p == dir {
+				return filepath.SkipDir
+			}
+
+			return err
+		}
+
+		if d.IsDir() {
+			return nil
+		}
+
+		relPath, err := filepath.Rel(dir, p)
+		if err != nil {
+			return err
+		}
+
+		relPath = filepath.ToSlash(relPath)
+		if !filter(relPath) {
+			return nil
+		}
+
+		file, err := os.Open(p)
+		if err != nil {
+			return err
+		}
+		defer file.Close()
+
+		checksum, err := md5Checksum(file)
+		if err != nil {
+			return err
+		}
+
+		checksums[relPath] = checksum
+
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", dir, err)
+	}
+
+	return checksums, nil
+}
+
+// zipChecksums returns the MD5 checksums and the entries of the files of a zip archive selected by the filter,
+// keyed by slash-separated path.
+func zipChecksums(reader *zip.Reader, filter func(relPath string) bool) (map[string]string, map[string]*zip.File, error) {
+	checksums := map[string]string{}
+	files := map[string]*zip.File{}
+
+	for _, file := range reader.File {
+		if file.FileInfo().IsDir() {
+			continue
+		}
+
+		relPath := path.Clean(file.Name)
+		if !filter(relPath) {
+			continue
+		}
+
+		rc, err := file.Open()
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to read %s from zip: %w", file.Name, err)
+		}
+
+		checksum, err := md5Checksum(rc)
+		rc.Close()
+
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to read %s from zip: %w", file.Name, err)
+		}
+
+		checksums[relPath] = checksum
+		files[relPath] = file
+	}
+
+	return checksums, files, nil
+}
+
+// readZipFile returns the content of a zip entry.
+func readZipFile(file *zip.File) ([]byte, error) {
+	rc, err := file.Open()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s from zip: %w", file.Name, err)
+	}
+	defer rc.Close()
+
+	return io.ReadAll(rc)
+}
+
+// md5Checksum returns the hex-encoded MD5 checksum of the content, which is comparable with S3 ETags.
+func md5Checksum(r io.Reader) (string, error) {
+	hash := md5.New() //nolint:gosec // S3 ETags are MD5 checksums
+	if _, err := io.Copy(hash, r); err != nil {
+		return "", err
+	}
+
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
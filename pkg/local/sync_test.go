@@ -0,0 +1,148 @@
+package local
+
+import (
+	"archive/zip"
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/hupe1980/mwaacli/pkg/util"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLocalChanges(t *testing.T) {
+	remote := map[string]string{
+		"unchanged.py":        "aaa",
+		"remote_update.py":    "bbb2",
+		"local_edit.py":       "ccc",
+		"both_edited.py":      "ddd2",
+		"deleted_locally.py":  "eee",
+		"already_equal.py":    "fff2",
+		"collides.py":         "ggg",
+		"remote_and_local.py": "hhh",
+	}
+
+	manifest := map[string]string{
+		"unchanged.py":         "aaa",
+		"remote_update.py":     "bbb",
+		"local_edit.py":        "ccc",
+		"both_edited.py":       "ddd",
+		"deleted_locally.py":   "eee",
+		"already_equal.py":     "fff",
+		"deleted_on_remote.py": "iii",
+		"remote_and_local.py":  "hhh",
+	}
+
+	local := map[string]string{
+		"unchanged.py":         "aaa",
+		"remote_update.py":     "bbb",
+		"local_edit.py":        "ccc2",
+		"both_edited.py":       "ddd3",
+		"already_equal.py":     "fff2",
+		"collides.py":          "ggg2",
+		"new.py":               "jjj",
+		"deleted_on_remote.py": "iii",
+	}
+
+	t.Run("With manifest", func(t *testing.T) {
+		assert.Equal(t, []LocalChange{
+			{Path: "both_edited.py", Kind: "modified"},
+			{Path: "collides.py", Kind: "modified"},
+			{Path: "deleted_locally.py", Kind: "deleted"},
+			{Path: "local_edit.py", Kind: "modified"},
+			{Path: "new.py", Kind: "added"},
+			{Path: "remote_and_local.py", Kind: "deleted"},
+		}, localChanges(manifest, local, remote))
+	})
+
+	t.Run("Without manifest", func(t *testing.T) {
+		assert.Equal(t, []LocalChange{
+			{Path: "both_edited.py", Kind: "modified"},
+			{Path: "collides.py", Kind: "modified"},
+			{Path: "deleted_on_remote.py", Kind: "added"},
+			{Path: "local_edit.py", Kind: "modified"},
+			{Path: "new.py", Kind: "added"},
+			{Path: "remote_update.py", Kind: "modified"},
+		}, localChanges(nil, local, remote))
+	})
+}
+
+func TestDetectLocalChanges(t *testing.T) {
+	remoteDir := t.TempDir()
+	assert.NoError(t, os.MkdirAll(filepath.Join(remoteDir, "operators"), 0755))
+	assert.NoError(t, os.WriteFile(filepath.Join(remoteDir, "__init__.py"), []byte(""), 0600))
+	assert.NoError(t, os.WriteFile(filepath.Join(remoteDir, "operators", "custom.py"), []byte("v1\n"), 0600))
+
+	var buf bytes.Buffer
+	assert.NoError(t, util.ZipDirectory(remoteDir, &buf))
+
+	reader, err := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	assert.NoError(t, err)
+
+	filter, err := util.NewPathFilter(remoteDir, nil, compiledPythonGlobs, false)
+	assert.NoError(t, err)
+
+	remote, files, err := zipChecksums(reader, filter.Match)
+	assert.NoError(t, err)
+	assert.Len(t, remote, 2)
+
+	readRemote := func(relPath string) ([]byte, error) {
+		return readZipFile(files[relPath])
+	}
+
+	localDir := filepath.Join(t.TempDir(), "plugins")
+	manifestPath := filepath.Join(t.TempDir(), ".sync", "plugins.json")
+
+	t.Run("Missing directory", func(t *testing.T) {
+		changes, err := detectLocalChanges(localDir, manifestPath, filter.Match, remote, readRemote)
+		assert.NoError(t, err)
+		assert.Empty(t, changes)
+	})
+
+	// Simulate a sync followed by a local run and a local edit
+	assert.NoError(t, util.Unzip(buf.Bytes(), localDir))
+	assert.NoError(t, writeSyncManifest(manifestPath, localDir, filter.Match))
+	assert.NoError(t, os.MkdirAll(filepath.Join(localDir, "operators", "__pycache__"), 0755))
+	assert.NoError(t, os.WriteFile(filepath.Join(localDir, "operators", "__pycache__", "custom.cpython-311.pyc"), []byte{0}, 0600))
+
+	t.Run("Compiled Python files", func(t *testing.T) {
+		changes, err := detectLocalChanges(localDir, manifestPath, filter.Match, remote, readRemote)
+		assert.NoError(t, err)
+		assert.Empty(t, changes)
+	})
+
+	t.Run("Remote update", func(t *testing.T) {
+		updated := map[string]string{"__init__.py": remote["__init__.py"], "operators/custom.py": "other"}
+
+		changes, err := detectLocalChanges(localDir, manifestPath, filter.Match, updated, readRemote)
+		assert.NoError(t, err)
+		assert.Empty(t, changes)
+	})
+
+	t.Run("Local edit", func(t *testing.T) {
+		assert.NoError(t, os.WriteFile(filepath.Join(localDir, "operators", "custom.py"), []byte("v2\n"), 0600))
+
+		changes, err := detectLocalChanges(localDir, manifestPath, filter.Match, remote, readRemote)
+		assert.NoError(t, err)
+		assert.Equal(t, []LocalChange{{
+			Path: "operators/custom.py",
+			Kind: "modified",
+			Diff: "--- remote/operators/custom.py\n+++ local/operators/custom.py\n@@ -1 +1 @@\n-v1\n+v2\n",
+		}}, changes)
+	})
+}
+
+func TestReadSyncManifest(t *testing.T) {
+	manifest, err := readSyncManifest(filepath.Join(t.TempDir(), "missing.json"))
+	assert.NoError(t, err)
+	assert.Nil(t, manifest)
+
+	manifestPath := filepath.Join(t.TempDir(), "dags.json")
+	assert.NoError(t, os.WriteFile(manifestPath, []byte(`{"files": {}}`), 0600))
+
+	manifest, err = readSyncManifest(manifestPath)
+	assert.NoError(t, err)
+	assert.NotNil(t, manifest)
+	assert.Empty(t, manifest)
+}
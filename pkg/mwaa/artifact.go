@@ -0,0 +1,78 @@
+package mwaa
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	awsmwaa "github.com/aws/aws-sdk-go-v2/service/mwaa"
+	"github.com/aws/aws-sdk-go-v2/service/mwaa/types"
+)
+
+// Artifact is a versioned file in the environment's S3 bucket that the environment is pinned to.
+type Artifact string
+
+const (
+	ArtifactRequirements  Artifact = "requirements" // The requirements.txt file.
+	ArtifactPlugins       Artifact = "plugins"      // The plugins.zip file.
+	ArtifactStartupScript Artifact = "startup"      // The startup script.
+)
+
+// ParseArtifact parses the name of an artifact.
+func ParseArtifact(name string) (Artifact, error) {
+	switch artifact := Artifact(strings.ToLower(name)); artifact {
+	case ArtifactRequirements, ArtifactPlugins, ArtifactStartupScript:
+		return artifact, nil
+	default:
+		return "", fmt.Errorf("invalid artifact: %s, expected requirements, plugins or startup", name)
+	}
+}
+
+// S3Path returns the configured S3 path of the artifact, or an empty string if it is not configured.
+func (a Artifact) S3Path(environment *types.Environment) string {
+	switch a {
+	case ArtifactRequirements:
+		return aws.ToString(environment.RequirementsS3Path)
+	case ArtifactPlugins:
+		return aws.ToString(environment.PluginsS3Path)
+	case ArtifactStartupScript:
+		return aws.ToString(environment.StartupScriptS3Path)
+	default:
+		return ""
+	}
+}
+
+// ObjectVersion returns the S3 object version of the artifact the environment uses.
+func (a Artifact) ObjectVersion(environment *types.Environment) string {
+	switch a {
+	case ArtifactRequirements:
+		return aws.ToString(environment.RequirementsS3ObjectVersion)
+	case ArtifactPlugins:
+		return aws.ToString(environment.PluginsS3ObjectVersion)
+	case ArtifactStartupScript:
+		return aws.ToString(environment.StartupScriptS3ObjectVersion)
+	default:
+		return ""
+	}
+}
+
+// UpdateEnvironmentInput returns the input to point the environment to the given S3 path and object version of the artifact.
+func (a Artifact) UpdateEnvironmentInput(environmentName, s3Path, objectVersion string) *awsmwaa.UpdateEnvironmentInput {
+	input := &awsmwaa.UpdateEnvironmentInput{
+		Name: aws.String(environmentName),
+	}
+
+	switch a {
+	case ArtifactRequirements:
+		input.RequirementsS3Path = aws.String(s3Path)
+		input.RequirementsS3ObjectVersion = aws.String(objectVersion)
+	case ArtifactPlugins:
+		input.PluginsS3Path = aws.String(s3Path)
+		input.PluginsS3ObjectVersion = aws.String(objectVersion)
+	case ArtifactStartupScript:
+		input.StartupScriptS3Path = aws.String(s3Path)
+		input.StartupScriptS3ObjectVersion = aws.String(objectVersion)
+	}
+
+	return input
+}
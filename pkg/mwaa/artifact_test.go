@@ -0,0 +1,40 @@
+package mwaa
+
+import (
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/mwaa/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseArtifact(t *testing.T) {
+	artifact, err := ParseArtifact("Requirements")
+	assert.NoError(t, err)
+	assert.Equal(t, ArtifactRequirements, artifact)
+
+	_, err = ParseArtifact("dags")
+	assert.Error(t, err)
+}
+
+func TestArtifact(t *testing.T) {
+	environment := &types.Environment{
+		RequirementsS3Path:          aws.String("requirements.txt"),
+		RequirementsS3ObjectVersion: aws.String("v1"),
+		PluginsS3Path:               aws.String("plugins.zip"),
+		PluginsS3ObjectVersion:      aws.String("v2"),
+	}
+
+	assert.Equal(t, "requirements.txt", ArtifactRequirements.S3Path(environment))
+	assert.Equal(t, "v1", ArtifactRequirements.ObjectVersion(environment))
+	assert.Equal(t, "plugins.zip", ArtifactPlugins.S3Path(environment))
+	assert.Equal(t, "v2", ArtifactPlugins.ObjectVersion(environment))
+	assert.Empty(t, ArtifactStartupScript.S3Path(environment))
+	assert.Empty(t, ArtifactStartupScript.ObjectVersion(environment))
+
+	input := ArtifactPlugins.UpdateEnvironmentInput("analytics", "plugins.zip", "v3")
+	assert.Equal(t, "analytics", aws.ToString(input.Name))
+	assert.Equal(t, "plugins.zip", aws.ToString(input.PluginsS3Path))
+	assert.Equal(t, "v3", aws.ToString(input.PluginsS3ObjectVersion))
+	assert.Nil(t, input.RequirementsS3ObjectVersion)
+}
@@ -90,10 +90,34 @@ func (c *Client) handleRestAPIError(err error) error {
 func (c *Client) formatRestAPIError(response document.Interface, statusCode *int32) error {
 	var errorRsp map[string]any
 	if err := response.UnmarshalSmithyDocument(&errorRsp); err == nil {
-		return fmt.Errorf("%s: %s (HTTP StatusCode %d)", errorRsp["title"], errorRsp["detail"], aws.ToInt32(statusCode))
+		return &RestAPIError{
+			StatusCode: int(aws.ToInt32(statusCode)),
+			Message:    fmt.Sprintf("%s: %s", errorRsp["title"], errorRsp["detail"]),
+		}
+	}
+
+	return &RestAPIError{
+		StatusCode: int(aws.ToInt32(statusCode)),
+		Message:    fmt.Sprintf("%s", response),
 	}
+}
+
+// RestAPIError represents an error response of the MWAA environment's REST API.
+type RestAPIError struct {
+	StatusCode int    // The HTTP status code of the response.
+	Message    string // The error details extracted from the response.
+}
 
-	return fmt.Errorf("%s (HTTP StatusCode %d)", response, aws.ToInt32(statusCode))
+// Error implements the error interface.
+func (e *RestAPIError) Error() string {
+	return fmt.Sprintf("%s (HTTP StatusCode %d)", e.Message, e.StatusCode)
+}
+
+// IsNotFound reports whether the error is a REST API error with HTTP status code 404.
+func IsNotFound(err error) bool {
+	var restAPIErr *RestAPIError
+
+	return errors.As(err, &restAPIErr) && restAPIErr.StatusCode == http.StatusNotFound
 }
 
 // InvokeCliCommand executes a CLI command on the specified MWAA environment.
@@ -181,6 +205,26 @@ func (c *Client) GetEnvironment(ctx context.Context, environmentName string) (*t
 	return output.Environment, nil
 }
 
+// CreateEnvironment creates an MWAA environment and returns its ARN.
+func (c *Client) CreateEnvironment(ctx context.Context, input *awsmwaa.CreateEnvironmentInput) (string, error) {
+	output, err := c.client.CreateEnvironment(ctx, input)
+	if err != nil {
+		return "", err
+	}
+
+	return aws.ToString(output.Arn), nil
+}
+
+// UpdateEnvironment updates an MWAA environment and returns its ARN.
+func (c *Client) UpdateEnvironment(ctx context.Context, input *awsmwaa.UpdateEnvironmentInput) (string, error) {
+	output, err := c.client.UpdateEnvironment(ctx, input)
+	if err != nil {
+		return "", err
+	}
+
+	return aws.ToString(output.Arn), nil
+}
+
 // DeleteEnvironment removes an MWAA environment by its name.
 func (c *Client) DeleteEnvironment(ctx context.Context, environmentName string) error {
 	input := &awsmwaa.DeleteEnvironmentInput{
@@ -0,0 +1,78 @@
+package mwaa
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIsNotFound(t *testing.T) {
+	tests := []struct {
+		name     string
+		err      error
+		expected bool
+	}{
+		{
+			name:     "Not found error",
+			err:      &RestAPIError{StatusCode: 404, Message: "Variable not found: missing"},
+			expected: true,
+		},
+		{
+			name:     "Wrapped not found error",
+			err:      fmt.Errorf("failed: %w", &RestAPIError{StatusCode: 404}),
+			expected: true,
+		},
+		{
+			name:     "Other status code",
+			err:      &RestAPIError{StatusCode: 409},
+			expected: false,
+		},
+		{
+			name:     "Other error",
+			err:      errors.New("boom"),
+			expected: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, IsNotFound(tt.err))
+		})
+	}
+}
+
+func TestRestAPIErrorMessage(t *testing.T) {
+	err := &RestAPIError{StatusCode: 404, Message: "DAG not found: example"}
+	assert.EqualError(t, err, "DAG not found: example (HTTP StatusCode 404)")
+}
+
+func TestToInt(t *testing.T) {
+	tests := []struct {
+		name        string
+		value       any
+		expected    int
+		expectError bool
+	}{
+		{name: "Number", value: json.Number("42"), expected: 42},
+		{name: "Float", value: float64(7), expected: 7},
+		{name: "Int", value: 3, expected: 3},
+		{name: "Nil", value: nil, expected: 0},
+		{name: "String", value: "n/a", expectError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := ToInt(tt.value)
+
+			if tt.expectError {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.expected, result)
+			}
+		})
+	}
+}
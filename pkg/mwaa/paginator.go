@@ -0,0 +1,137 @@
+package mwaa
+
+import (
+	"context"
+	"fmt"
+	"maps"
+)
+
+// RestAPIPaginatorOptions defines the options for a RestAPIPaginator.
+type RestAPIPaginatorOptions struct {
+	// Limit is the maximum number of items requested per page.
+	Limit int
+
+	// Offset is the number of items to skip before the first page.
+	Offset int
+}
+
+// RestAPIGetter is the interface required by a RestAPIPaginator to fetch a page.
+// It is implemented by Client.
+type RestAPIGetter interface {
+	RestAPIGet(ctx context.Context, environmentName, path string, queryParams map[string]any, response any) error
+}
+
+// RestAPIPaginator pages through a collection endpoint of the Airflow REST API
+// (e.g. /dags or /variables) using the limit and offset query parameters.
+// It stops once the offset reaches the total_entries reported by the API.
+type RestAPIPaginator struct {
+	client          RestAPIGetter
+	environmentName string
+	path            string
+	collectionKey   string
+	queryParams     map[string]any
+	opts            RestAPIPaginatorOptions
+	offset          int
+	firstPage       bool
+	done            bool
+}
+
+// NewRestAPIPaginator creates a new paginator for the collection at the given path.
+// The collectionKey names the field of the response that holds the items (e.g. "dags").
+func NewRestAPIPaginator(client RestAPIGetter, environmentName, path, collectionKey string, queryParams map[string]any, optFns ...func(o *RestAPIPaginatorOptions)) *RestAPIPaginator {
+	opts := RestAPIPaginatorOptions{
+		Limit: 100,
+	}
+
+	for _, fn := range optFns {
+		fn(&opts)
+	}
+
+	return &RestAPIPaginator{
+		client:          client,
+		environmentName: environmentName,
+		path:            path,
+		collectionKey:   collectionKey,
+		queryParams:     queryParams,
+		opts:            opts,
+		offset:          opts.Offset,
+		firstPage:       true,
+	}
+}
+
+// HasMorePages reports whether more pages are available.
+func (p *RestAPIPaginator) HasMorePages() bool {
+	return p.firstPage || !p.done
+}
+
+// NextPage retrieves the next page of items.
+func (p *RestAPIPaginator) NextPage(ctx context.Context) ([]map[string]any, error) {
+	if !p.HasMorePages() {
+		return nil, fmt.Errorf("no more pages available")
+	}
+
+	queryParams := maps.Clone(p.queryParams)
+	if queryParams == nil {
+		queryParams = map[string]any{}
+	}
+
+	queryParams["limit"] = p.opts.Limit
+	queryParams["offset"] = p.offset
+
+	var response map[string]any
+	if err := p.client.RestAPIGet(ctx, p.environmentName, p.path, queryParams, &response); err != nil {
+		return nil, err
+	}
+
+	items, err := collectionItems(response, p.collectionKey)
+	if err != nil {
+		return nil, err
+	}
+
+	totalEntries, err := collectionTotalEntries(response)
+	if err != nil {
+		return nil, err
+	}
+
+	p.firstPage = false
+	p.offset += len(items)
+	p.done = len(items) == 0 || p.offset >= totalEntries
+
+	return items, nil
+}
+
+// collectionItems extracts the items stored under the collection key of a response.
+func collectionItems(response map[string]any, collectionKey string) ([]map[string]any, error) {
+	raw, ok := response[collectionKey]
+	if !ok || raw == nil {
+		return []map[string]any{}, nil
+	}
+
+	list, ok := raw.([]any)
+	if !ok {
+		return nil, fmt.Errorf("unexpected type %T for collection %s", raw, collectionKey)
+	}
+
+	items := make([]map[string]any, 0, len(list))
+
+	for _, item := range list {
+		obj, ok := item.(map[string]any)
+		if !ok {
+			return nil, fmt.Errorf("unexpected type %T for item of collection %s", item, collectionKey)
+		}
+
+		items = append(items, obj)
+	}
+
+	return items, nil
+}
+
+// collectionTotalEntries extracts the total_entries field of a response.
+func collectionTotalEntries(response map[string]any) (int, error) {
+	totalEntries, err := ToInt(response["total_entries"])
+	if err != nil {
+		return 0, fmt.Errorf("invalid total_entries: %w", err)
+	}
+
+	return totalEntries, nil
+}
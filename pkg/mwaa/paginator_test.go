@@ -0,0 +1,223 @@
+package mwaa
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCollectionItems(t *testing.T) {
+	tests := []struct {
+		name        string
+		response    map[string]any
+		expected    []map[string]any
+		expectError bool
+	}{
+		{
+			name: "Items present",
+			response: map[string]any{
+				"dags": []any{
+					map[string]any{"dag_id": "a"},
+					map[string]any{"dag_id": "b"},
+				},
+			},
+			expected: []map[string]any{
+				{"dag_id": "a"},
+				{"dag_id": "b"},
+			},
+		},
+		{
+			name:     "Missing collection",
+			response: map[string]any{},
+			expected: []map[string]any{},
+		},
+		{
+			name:        "Unexpected collection type",
+			response:    map[string]any{"dags": "invalid"},
+			expectError: true,
+		},
+		{
+			name:        "Unexpected item type",
+			response:    map[string]any{"dags": []any{"invalid"}},
+			expectError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := collectionItems(tt.response, "dags")
+
+			if tt.expectError {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.expected, result)
+			}
+		})
+	}
+}
+
+func TestCollectionTotalEntries(t *testing.T) {
+	tests := []struct {
+		name        string
+		response    map[string]any
+		expected    int
+		expectError bool
+	}{
+		{
+			name:     "Arbitrary precision number",
+			response: map[string]any{"total_entries": json.Number("642")},
+			expected: 642,
+		},
+		{
+			name:     "Float value",
+			response: map[string]any{"total_entries": float64(12)},
+			expected: 12,
+		},
+		{
+			name:     "Missing total entries",
+			response: map[string]any{},
+			expected: 0,
+		},
+		{
+			name:        "Unexpected type",
+			response:    map[string]any{"total_entries": "many"},
+			expectError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := collectionTotalEntries(tt.response)
+
+			if tt.expectError {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.expected, result)
+			}
+		})
+	}
+}
+
+// fakeRestAPIGetter serves a collection of dag items with the given total_entries.
+type fakeRestAPIGetter struct {
+	dags         []string
+	totalEntries int
+	offsets      []int
+}
+
+func (f *fakeRestAPIGetter) RestAPIGet(_ context.Context, _, _ string, queryParams map[string]any, response any) error {
+	limit := queryParams["limit"].(int)
+	offset := queryParams["offset"].(int)
+
+	f.offsets = append(f.offsets, offset)
+
+	items := []any{}
+
+	for i := offset; i < len(f.dags) && i < offset+limit; i++ {
+		items = append(items, map[string]any{"dag_id": f.dags[i]})
+	}
+
+	*response.(*map[string]any) = map[string]any{
+		"dags":          items,
+		"total_entries": float64(f.totalEntries),
+	}
+
+	return nil
+}
+
+func newFakeDags(n int) []string {
+	dags := make([]string, n)
+	for i := range dags {
+		dags[i] = fmt.Sprintf("dag_%d", i)
+	}
+
+	return dags
+}
+
+func TestRestAPIPaginator(t *testing.T) {
+	tests := []struct {
+		name            string
+		dags            []string
+		totalEntries    int
+		limit           int
+		offset          int
+		expectedCount   int
+		expectedOffsets []int
+	}{
+		{
+			name:            "Stops at total_entries",
+			dags:            newFakeDags(5),
+			totalEntries:    5,
+			limit:           2,
+			expectedCount:   5,
+			expectedOffsets: []int{0, 2, 4},
+		},
+		{
+			name:            "Stops on exact page boundary",
+			dags:            newFakeDags(4),
+			totalEntries:    4,
+			limit:           2,
+			expectedCount:   4,
+			expectedOffsets: []int{0, 2},
+		},
+		{
+			name:            "Stops on empty page",
+			dags:            newFakeDags(3),
+			totalEntries:    10,
+			limit:           2,
+			expectedCount:   3,
+			expectedOffsets: []int{0, 2, 3},
+		},
+		{
+			name:            "Honours starting offset",
+			dags:            newFakeDags(5),
+			totalEntries:    5,
+			limit:           2,
+			offset:          3,
+			expectedCount:   2,
+			expectedOffsets: []int{3},
+		},
+		{
+			name:            "Empty collection",
+			totalEntries:    0,
+			limit:           2,
+			expectedCount:   0,
+			expectedOffsets: []int{0},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := &fakeRestAPIGetter{dags: tt.dags, totalEntries: tt.totalEntries}
+
+			paginator := NewRestAPIPaginator(client, "env", "/dags", "dags", nil, func(o *RestAPIPaginatorOptions) {
+				o.Limit = tt.limit
+				o.Offset = tt.offset
+			})
+
+			var items []map[string]any
+
+			for paginator.HasMorePages() {
+				page, err := paginator.NextPage(context.Background())
+				assert.NoError(t, err)
+
+				items = append(items, page...)
+			}
+
+			assert.Len(t, items, tt.expectedCount)
+			assert.Equal(t, tt.expectedOffsets, client.offsets)
+
+			if tt.expectedCount > 0 {
+				assert.Equal(t, fmt.Sprintf("dag_%d", tt.offset), items[0]["dag_id"])
+			}
+
+			_, err := paginator.NextPage(context.Background())
+			assert.Error(t, err)
+		})
+	}
+}
@@ -2,6 +2,7 @@ package mwaa
 
 import (
 	"context"
+	"fmt"
 
 	"github.com/aws/aws-sdk-go-v2/service/mwaa/types"
 )
@@ -25,3 +26,42 @@ func (c *Client) RestAPIPost(ctx context.Context, environmentName, path string,
 
 	return output.RestApiResponse.UnmarshalSmithyDocument(response)
 }
+
+// RestAPIPatch sends a PATCH request to the MWAA environment's REST API.
+func (c *Client) RestAPIPatch(ctx context.Context, environmentName, path string, queryParams map[string]any, body any, response any) error {
+	output, err := c.InvokeRestAPI(ctx, types.RestApiMethodPatch, environmentName, path, queryParams, body)
+	if err != nil {
+		return err
+	}
+
+	return output.RestApiResponse.UnmarshalSmithyDocument(response)
+}
+
+// RestAPIDelete sends a DELETE request to the MWAA environment's REST API.
+func (c *Client) RestAPIDelete(ctx context.Context, environmentName, path string, queryParams map[string]any) error {
+	_, err := c.InvokeRestAPI(ctx, types.RestApiMethodDelete, environmentName, path, queryParams, nil)
+
+	return err
+}
+
+// ToInt converts a numeric value decoded from a REST API response to an int.
+// Responses may hold document.Number, float64 or int values. A nil value yields 0.
+func ToInt(v any) (int, error) {
+	switch n := v.(type) {
+	case nil:
+		return 0, nil
+	case interface{ Int64() (int64, error) }: // document.Number
+		i, err := n.Int64()
+		if err != nil {
+			return 0, err
+		}
+
+		return int(i), nil
+	case float64:
+		return int(n), nil
+	case int:
+		return n, nil
+	default:
+		return 0, fmt.Errorf("unexpected type %T for numeric value", v)
+	}
+}
@@ -0,0 +1,378 @@
+package mwaa
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	awsmwaa "github.com/aws/aws-sdk-go-v2/service/mwaa"
+	"github.com/aws/aws-sdk-go-v2/service/mwaa/types"
+	"gopkg.in/yaml.v3"
+)
+
+// EnvironmentSpec is a declarative description of an MWAA environment.
+// It is read from YAML or JSON spec files and converted to the input of the
+// CreateEnvironment and UpdateEnvironment APIs. Unset fields are left to the service defaults
+// on create and left unchanged on update.
+type EnvironmentSpec struct {
+	Name                         string            `yaml:"name" json:"name"`
+	AirflowVersion               *string           `yaml:"airflowVersion,omitempty" json:"airflowVersion,omitempty"`
+	EnvironmentClass             *string           `yaml:"environmentClass,omitempty" json:"environmentClass,omitempty"`
+	MinWorkers                   *int32            `yaml:"minWorkers,omitempty" json:"minWorkers,omitempty"`
+	MaxWorkers                   *int32            `yaml:"maxWorkers,omitempty" json:"maxWorkers,omitempty"`
+	MinWebservers                *int32            `yaml:"minWebservers,omitempty" json:"minWebservers,omitempty"`
+	MaxWebservers                *int32            `yaml:"maxWebservers,omitempty" json:"maxWebservers,omitempty"`
+	Schedulers                   *int32            `yaml:"schedulers,omitempty" json:"schedulers,omitempty"`
+	AirflowConfigurationOptions  map[string]string `yaml:"airflowConfigurationOptions,omitempty" json:"airflowConfigurationOptions,omitempty"`
+	ExecutionRoleArn             *string           `yaml:"executionRoleArn,omitempty" json:"executionRoleArn,omitempty"`
+	SourceBucketArn              *string           `yaml:"sourceBucketArn,omitempty" json:"sourceBucketArn,omitempty"`
+	DagS3Path                    *string           `yaml:"dagS3Path,omitempty" json:"dagS3Path,omitempty"`
+	PluginsS3Path                *string           `yaml:"pluginsS3Path,omitempty" json:"pluginsS3Path,omitempty"`
+	PluginsS3ObjectVersion       *string           `yaml:"pluginsS3ObjectVersion,omitempty" json:"pluginsS3ObjectVersion,omitempty"`
+	RequirementsS3Path           *string           `yaml:"requirementsS3Path,omitempty" json:"requirementsS3Path,omitempty"`
+	RequirementsS3ObjectVersion  *string           `yaml:"requirementsS3ObjectVersion,omitempty" json:"requirementsS3ObjectVersion,omitempty"`
+	StartupScriptS3Path          *string           `yaml:"startupScriptS3Path,omitempty" json:"startupScriptS3Path,omitempty"`
+	StartupScriptS3ObjectVersion *string           `yaml:"startupScriptS3ObjectVersion,omitempty" json:"startupScriptS3ObjectVersion,omitempty"`
+	KmsKey                       *string           `yaml:"kmsKey,omitempty" json:"kmsKey,omitempty"`
+	WebserverAccessMode          string            `yaml:"webserverAccessMode,omitempty" json:"webserverAccessMode,omitempty"`
+	EndpointManagement           string            `yaml:"endpointManagement,omitempty" json:"endpointManagement,omitempty"`
+	WeeklyMaintenanceWindowStart *string           `yaml:"weeklyMaintenanceWindowStart,omitempty" json:"weeklyMaintenanceWindowStart,omitempty"`
+	NetworkConfiguration         *NetworkSpec      `yaml:"networkConfiguration,omitempty" json:"networkConfiguration,omitempty"`
+	LoggingConfiguration         *LoggingSpec      `yaml:"loggingConfiguration,omitempty" json:"loggingConfiguration,omitempty"`
+	Tags                         map[string]string `yaml:"tags,omitempty" json:"tags,omitempty"`
+}
+
+// NetworkSpec describes the network configuration of an environment.
+type NetworkSpec struct {
+	SubnetIDs        []string `yaml:"subnetIds,omitempty" json:"subnetIds,omitempty"`
+	SecurityGroupIDs []string `yaml:"securityGroupIds,omitempty" json:"securityGroupIds,omitempty"`
+}
+
+// LoggingSpec describes the logging configuration of an environment per Airflow module.
+type LoggingSpec struct {
+	DagProcessingLogs *ModuleLoggingSpec `yaml:"dagProcessingLogs,omitempty" json:"dagProcessingLogs,omitempty"`
+	SchedulerLogs     *ModuleLoggingSpec `yaml:"schedulerLogs,omitempty" json:"schedulerLogs,omitempty"`
+	TaskLogs          *ModuleLoggingSpec `yaml:"taskLogs,omitempty" json:"taskLogs,omitempty"`
+	WebserverLogs     *ModuleLoggingSpec `yaml:"webserverLogs,omitempty" json:"webserverLogs,omitempty"`
+	WorkerLogs        *ModuleLoggingSpec `yaml:"workerLogs,omitempty" json:"workerLogs,omitempty"`
+}
+
+// ModuleLoggingSpec describes the logging configuration of a single Airflow module.
+type ModuleLoggingSpec struct {
+	Enabled  *bool  `yaml:"enabled,omitempty" json:"enabled,omitempty"`
+	LogLevel string `yaml:"logLevel,omitempty" json:"logLevel,omitempty"`
+}
+
+// NewEnvironmentSpec converts an environment returned by GetEnvironment to a spec.
+// Read-only fields such as the ARN, status, timestamps and log group ARNs are not part of a spec.
+func NewEnvironmentSpec(environment *types.Environment) *EnvironmentSpec {
+	spec := &EnvironmentSpec{
+		Name:                         aws.ToString(environment.Name),
+		AirflowVersion:               environment.AirflowVersion,
+		EnvironmentClass:             environment.EnvironmentClass,
+		MinWorkers:                   environment.MinWorkers,
+		MaxWorkers:                   environment.MaxWorkers,
+		MinWebservers:                environment.MinWebservers,
+		MaxWebservers:                environment.MaxWebservers,
+		Schedulers:                   environment.Schedulers,
+		AirflowConfigurationOptions:  environment.AirflowConfigurationOptions,
+		ExecutionRoleArn:             environment.ExecutionRoleArn,
+		SourceBucketArn:              environment.SourceBucketArn,
+		DagS3Path:                    environment.DagS3Path,
+		PluginsS3Path:                environment.PluginsS3Path,
+		PluginsS3ObjectVersion:       environment.PluginsS3ObjectVersion,
+		RequirementsS3Path:           environment.RequirementsS3Path,
+		RequirementsS3ObjectVersion:  environment.RequirementsS3ObjectVersion,
+		StartupScriptS3Path:          environment.StartupScriptS3Path,
+		StartupScriptS3ObjectVersion: environment.StartupScriptS3ObjectVersion,
+		KmsKey:                       environment.KmsKey,
+		WebserverAccessMode:          string(environment.WebserverAccessMode),
+		EndpointManagement:           string(environment.EndpointManagement),
+		WeeklyMaintenanceWindowStart: environment.WeeklyMaintenanceWindowStart,
+		Tags:                         environment.Tags,
+	}
+
+	if network := environment.NetworkConfiguration; network != nil {
+		spec.NetworkConfiguration = &NetworkSpec{
+			SubnetIDs:        network.SubnetIds,
+			SecurityGroupIDs: network.SecurityGroupIds,
+		}
+	}
+
+	if logging := environment.LoggingConfiguration; logging != nil {
+		spec.LoggingConfiguration = &LoggingSpec{
+			DagProcessingLogs: newModuleLoggingSpec(logging.DagProcessingLogs),
+			SchedulerLogs:     newModuleLoggingSpec(logging.SchedulerLogs),
+			TaskLogs:          newModuleLoggingSpec(logging.TaskLogs),
+			WebserverLogs:     newModuleLoggingSpec(logging.WebserverLogs),
+			WorkerLogs:        newModuleLoggingSpec(logging.WorkerLogs),
+		}
+	}
+
+	return spec
+}
+
+// newModuleLoggingSpec converts the logging configuration of a single Airflow module to a spec.
+func newModuleLoggingSpec(config *types.ModuleLoggingConfiguration) *ModuleLoggingSpec {
+	if config == nil {
+		return nil
+	}
+
+	return &ModuleLoggingSpec{
+		Enabled:  config.Enabled,
+		LogLevel: string(config.LogLevel),
+	}
+}
+
+// Placeholders used by Templatize for account-specific fields. ParseEnvironmentSpec expands them,
+// like any other ${NAME} placeholder, from the environment variables.
+const (
+	ExecutionRoleArnPlaceholder = "${EXECUTION_ROLE_ARN}"
+	SourceBucketArnPlaceholder  = "${SOURCE_BUCKET_ARN}"
+	KmsKeyPlaceholder           = "${KMS_KEY}"
+	SubnetIDPlaceholder         = "${SUBNET_ID}"
+	SecurityGroupIDPlaceholder  = "${SECURITY_GROUP_ID}"
+)
+
+// Templatize replaces the account-specific fields of the spec with placeholders, so it can be used as
+// a template for an environment in another account. The S3 object versions are removed, because they
+// only exist in the original bucket.
+func (s *EnvironmentSpec) Templatize() {
+	s.ExecutionRoleArn = aws.String(ExecutionRoleArnPlaceholder)
+	s.SourceBucketArn = aws.String(SourceBucketArnPlaceholder)
+
+	if s.KmsKey != nil {
+		s.KmsKey = aws.String(KmsKeyPlaceholder)
+	}
+
+	if s.NetworkConfiguration != nil {
+		s.NetworkConfiguration = &NetworkSpec{
+			SubnetIDs:        placeholders(SubnetIDPlaceholder, len(s.NetworkConfiguration.SubnetIDs)),
+			SecurityGroupIDs: placeholders(SecurityGroupIDPlaceholder, len(s.NetworkConfiguration.SecurityGroupIDs)),
+		}
+	}
+
+	s.PluginsS3ObjectVersion = nil
+	s.RequirementsS3ObjectVersion = nil
+	s.StartupScriptS3ObjectVersion = nil
+}
+
+// placeholders returns n numbered placeholders derived from the given one, e.g. ${SUBNET_ID_1}.
+func placeholders(placeholder string, n int) []string {
+	if n == 0 {
+		return nil
+	}
+
+	result := make([]string, n)
+	for i := range result {
+		result[i] = fmt.Sprintf("%s_%d}", strings.TrimSuffix(placeholder, "}"), i+1)
+	}
+
+	return result
+}
+
+// LoadEnvironmentSpec reads and parses an environment spec file from the given file path.
+// It internally uses ParseEnvironmentSpec to parse the file content.
+func LoadEnvironmentSpec(filePath string) (*EnvironmentSpec, error) {
+	file, err := os.Open(filePath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open file: %w", err)
+	}
+	defer file.Close()
+
+	return ParseEnvironmentSpec(file)
+}
+
+// ParseEnvironmentSpec reads and parses an environment spec in YAML or JSON format from an io.Reader.
+// Placeholders of the form ${NAME} are replaced with the value of the environment variable NAME,
+// and an error is returned if one of them is not set. Unknown fields are rejected to catch typos in spec files.
+func ParseEnvironmentSpec(reader io.Reader) (*EnvironmentSpec, error) {
+	data, err := io.ReadAll(reader)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read data: %w", err)
+	}
+
+	data, err = expandPlaceholders(data, os.LookupEnv)
+	if err != nil {
+		return nil, err
+	}
+
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	decoder.KnownFields(true)
+
+	var spec EnvironmentSpec
+	if err := decoder.Decode(&spec); err != nil {
+		if errors.Is(err, io.EOF) {
+			return nil, fmt.Errorf("environment spec is empty")
+		}
+
+		return nil, fmt.Errorf("failed to parse environment spec: %w", err)
+	}
+
+	if spec.Name == "" {
+		return nil, fmt.Errorf("environment spec is missing the name")
+	}
+
+	return &spec, nil
+}
+
+// placeholderPattern matches a ${NAME} placeholder and captures the name.
+var placeholderPattern = regexp.MustCompile(`\$\{([A-Za-z_][A-Za-z0-9_]*)\}`)
+
+// expandPlaceholders replaces the ${NAME} placeholders in data with the values returned by lookup.
+// All placeholders without a value are reported in a single error.
+func expandPlaceholders(data []byte, lookup func(name string) (string, bool)) ([]byte, error) {
+	missing := map[string]bool{}
+
+	expanded := placeholderPattern.ReplaceAllFunc(data, func(match []byte) []byte {
+		name := string(placeholderPattern.FindSubmatch(match)[1])
+
+		value, ok := lookup(name)
+		if !ok {
+			missing[name] = true
+			return match
+		}
+
+		return []byte(value)
+	})
+
+	if len(missing) > 0 {
+		names := make([]string, 0, len(missing))
+		for name := range missing {
+			names = append(names, name)
+		}
+
+		sort.Strings(names)
+
+		return nil, fmt.Errorf("environment spec uses unset environment variables: %s", strings.Join(names, ", "))
+	}
+
+	return expanded, nil
+}
+
+// CreateEnvironmentInput converts the spec to the input of the CreateEnvironment API.
+// It returns an error if a field required to create an environment is missing.
+func (s *EnvironmentSpec) CreateEnvironmentInput() (*awsmwaa.CreateEnvironmentInput, error) {
+	var missing []string
+
+	if s.ExecutionRoleArn == nil {
+		missing = append(missing, "executionRoleArn")
+	}
+
+	if s.SourceBucketArn == nil {
+		missing = append(missing, "sourceBucketArn")
+	}
+
+	if s.DagS3Path == nil {
+		missing = append(missing, "dagS3Path")
+	}
+
+	if s.NetworkConfiguration == nil || len(s.NetworkConfiguration.SubnetIDs) == 0 || len(s.NetworkConfiguration.SecurityGroupIDs) == 0 {
+		missing = append(missing, "networkConfiguration")
+	}
+
+	if len(missing) > 0 {
+		return nil, fmt.Errorf("environment spec is missing required fields for create: %s", strings.Join(missing, ", "))
+	}
+
+	return &awsmwaa.CreateEnvironmentInput{
+		Name:                         &s.Name,
+		AirflowVersion:               s.AirflowVersion,
+		EnvironmentClass:             s.EnvironmentClass,
+		MinWorkers:                   s.MinWorkers,
+		MaxWorkers:                   s.MaxWorkers,
+		MinWebservers:                s.MinWebservers,
+		MaxWebservers:                s.MaxWebservers,
+		Schedulers:                   s.Schedulers,
+		AirflowConfigurationOptions:  s.AirflowConfigurationOptions,
+		ExecutionRoleArn:             s.ExecutionRoleArn,
+		SourceBucketArn:              s.SourceBucketArn,
+		DagS3Path:                    s.DagS3Path,
+		PluginsS3Path:                s.PluginsS3Path,
+		PluginsS3ObjectVersion:       s.PluginsS3ObjectVersion,
+		RequirementsS3Path:           s.RequirementsS3Path,
+		RequirementsS3ObjectVersion:  s.RequirementsS3ObjectVersion,
+		StartupScriptS3Path:          s.StartupScriptS3Path,
+		StartupScriptS3ObjectVersion: s.StartupScriptS3ObjectVersion,
+		KmsKey:                       s.KmsKey,
+		WebserverAccessMode:          types.WebserverAccessMode(s.WebserverAccessMode),
+		EndpointManagement:           types.EndpointManagement(s.EndpointManagement),
+		WeeklyMaintenanceWindowStart: s.WeeklyMaintenanceWindowStart,
+		NetworkConfiguration: &types.NetworkConfiguration{
+			SubnetIds:        s.NetworkConfiguration.SubnetIDs,
+			SecurityGroupIds: s.NetworkConfiguration.SecurityGroupIDs,
+		},
+		LoggingConfiguration: s.LoggingConfiguration.input(),
+		Tags:                 s.Tags,
+	}, nil
+}
+
+// UpdateEnvironmentInput converts the spec to the input of the UpdateEnvironment API.
+// Fields that can only be set on create (subnets, KMS key, endpoint management and tags) are ignored,
+// so the same spec can be used to create and to update an environment.
+func (s *EnvironmentSpec) UpdateEnvironmentInput() *awsmwaa.UpdateEnvironmentInput {
+	input := &awsmwaa.UpdateEnvironmentInput{
+		Name:                         &s.Name,
+		AirflowVersion:               s.AirflowVersion,
+		EnvironmentClass:             s.EnvironmentClass,
+		MinWorkers:                   s.MinWorkers,
+		MaxWorkers:                   s.MaxWorkers,
+		MinWebservers:                s.MinWebservers,
+		MaxWebservers:                s.MaxWebservers,
+		Schedulers:                   s.Schedulers,
+		AirflowConfigurationOptions:  s.AirflowConfigurationOptions,
+		ExecutionRoleArn:             s.ExecutionRoleArn,
+		SourceBucketArn:              s.SourceBucketArn,
+		DagS3Path:                    s.DagS3Path,
+		PluginsS3Path:                s.PluginsS3Path,
+		PluginsS3ObjectVersion:       s.PluginsS3ObjectVersion,
+		RequirementsS3Path:           s.RequirementsS3Path,
+		RequirementsS3ObjectVersion:  s.RequirementsS3ObjectVersion,
+		StartupScriptS3Path:          s.StartupScriptS3Path,
+		StartupScriptS3ObjectVersion: s.StartupScriptS3ObjectVersion,
+		WebserverAccessMode:          types.WebserverAccessMode(s.WebserverAccessMode),
+		WeeklyMaintenanceWindowStart: s.WeeklyMaintenanceWindowStart,
+		LoggingConfiguration:         s.LoggingConfiguration.input(),
+	}
+
+	if s.NetworkConfiguration != nil && len(s.NetworkConfiguration.SecurityGroupIDs) > 0 {
+		input.NetworkConfiguration = &types.UpdateNetworkConfigurationInput{
+			SecurityGroupIds: s.NetworkConfiguration.SecurityGroupIDs,
+		}
+	}
+
+	return input
+}
+
+// input converts the logging spec to the logging configuration input of the MWAA APIs.
+func (l *LoggingSpec) input() *types.LoggingConfigurationInput {
+	if l == nil {
+		return nil
+	}
+
+	return &types.LoggingConfigurationInput{
+		DagProcessingLogs: l.DagProcessingLogs.input(),
+		SchedulerLogs:     l.SchedulerLogs.input(),
+		TaskLogs:          l.TaskLogs.input(),
+		WebserverLogs:     l.WebserverLogs.input(),
+		WorkerLogs:        l.WorkerLogs.input(),
+	}
+}
+
+// input converts the module logging spec to the module logging configuration input of the MWAA APIs.
+func (m *ModuleLoggingSpec) input() *types.ModuleLoggingConfigurationInput {
+	if m == nil {
+		return nil
+	}
+
+	return &types.ModuleLoggingConfigurationInput{
+		Enabled:  m.Enabled,
+		LogLevel: types.LoggingLevel(m.LogLevel),
+	}
+}
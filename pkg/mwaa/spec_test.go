@@ -0,0 +1,189 @@
+package mwaa
+
+import (
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/mwaa/types"
+	"github.com/stretchr/testify/assert"
+)
+
+const testEnvironmentSpec = `
+name: analytics
+environmentClass: mw1.medium
+minWorkers: 1
+maxWorkers: 10
+schedulers: 2
+airflowConfigurationOptions:
+  core.default_timezone: utc
+executionRoleArn: arn:aws:iam::123456789012:role/mwaa
+sourceBucketArn: arn:aws:s3:::mwaa-bucket
+dagS3Path: dags
+requirementsS3Path: requirements.txt
+webserverAccessMode: PRIVATE_ONLY
+networkConfiguration:
+  subnetIds: [subnet-a, subnet-b]
+  securityGroupIds: [sg-a]
+loggingConfiguration:
+  schedulerLogs:
+    enabled: true
+    logLevel: INFO
+`
+
+func TestLoadEnvironmentSpec(t *testing.T) {
+	tempFile, err := os.CreateTemp("", "spec-*.yaml")
+	assert.NoError(t, err)
+	defer os.Remove(tempFile.Name())
+
+	_, err = tempFile.WriteString(testEnvironmentSpec)
+	assert.NoError(t, err)
+	assert.NoError(t, tempFile.Close())
+
+	spec, err := LoadEnvironmentSpec(tempFile.Name())
+	assert.NoError(t, err)
+	assert.Equal(t, "analytics", spec.Name)
+	assert.Equal(t, aws.Int32(10), spec.MaxWorkers)
+	assert.Equal(t, map[string]string{"core.default_timezone": "utc"}, spec.AirflowConfigurationOptions)
+	assert.Equal(t, []string{"subnet-a", "subnet-b"}, spec.NetworkConfiguration.SubnetIDs)
+	assert.Equal(t, "INFO", spec.LoggingConfiguration.SchedulerLogs.LogLevel)
+}
+
+func TestParseEnvironmentSpec(t *testing.T) {
+	t.Run("JSON", func(t *testing.T) {
+		spec, err := ParseEnvironmentSpec(strings.NewReader(`{"name": "analytics", "maxWorkers": 5}`))
+		assert.NoError(t, err)
+		assert.Equal(t, "analytics", spec.Name)
+		assert.Equal(t, aws.Int32(5), spec.MaxWorkers)
+	})
+
+	t.Run("Unknown field", func(t *testing.T) {
+		_, err := ParseEnvironmentSpec(strings.NewReader("name: analytics\nmaxWorker: 5\n"))
+		assert.Error(t, err)
+	})
+
+	t.Run("Missing name", func(t *testing.T) {
+		_, err := ParseEnvironmentSpec(strings.NewReader("maxWorkers: 5\n"))
+		assert.EqualError(t, err, "environment spec is missing the name")
+	})
+
+	t.Run("Empty", func(t *testing.T) {
+		_, err := ParseEnvironmentSpec(strings.NewReader(""))
+		assert.EqualError(t, err, "environment spec is empty")
+	})
+
+	t.Run("Placeholders", func(t *testing.T) {
+		t.Setenv("EXECUTION_ROLE_ARN", "arn:aws:iam::123456789012:role/mwaa")
+		t.Setenv("SUBNET_ID_1", "subnet-1")
+
+		spec, err := ParseEnvironmentSpec(strings.NewReader("name: analytics\nexecutionRoleArn: ${EXECUTION_ROLE_ARN}\nnetworkConfiguration:\n  subnetIds: [\"${SUBNET_ID_1}\"]\n"))
+		assert.NoError(t, err)
+		assert.Equal(t, "arn:aws:iam::123456789012:role/mwaa", aws.ToString(spec.ExecutionRoleArn))
+		assert.Equal(t, []string{"subnet-1"}, spec.NetworkConfiguration.SubnetIDs)
+	})
+
+	t.Run("Unset placeholders", func(t *testing.T) {
+		_, err := ParseEnvironmentSpec(strings.NewReader("name: analytics\nexecutionRoleArn: ${MWAACLI_TEST_UNSET_B}\nsourceBucketArn: ${MWAACLI_TEST_UNSET_A}\n"))
+		assert.EqualError(t, err, "environment spec uses unset environment variables: MWAACLI_TEST_UNSET_A, MWAACLI_TEST_UNSET_B")
+	})
+}
+
+func TestEnvironmentSpecCreateEnvironmentInput(t *testing.T) {
+	spec, err := ParseEnvironmentSpec(strings.NewReader(testEnvironmentSpec))
+	assert.NoError(t, err)
+
+	input, err := spec.CreateEnvironmentInput()
+	assert.NoError(t, err)
+	assert.Equal(t, "analytics", aws.ToString(input.Name))
+	assert.Equal(t, types.WebserverAccessModePrivateOnly, input.WebserverAccessMode)
+	assert.Equal(t, []string{"sg-a"}, input.NetworkConfiguration.SecurityGroupIds)
+	assert.Equal(t, types.LoggingLevelInfo, input.LoggingConfiguration.SchedulerLogs.LogLevel)
+	assert.Nil(t, input.LoggingConfiguration.TaskLogs)
+
+	_, err = (&EnvironmentSpec{Name: "analytics"}).CreateEnvironmentInput()
+	assert.EqualError(t, err, "environment spec is missing required fields for create: executionRoleArn, sourceBucketArn, dagS3Path, networkConfiguration")
+}
+
+func TestEnvironmentSpecUpdateEnvironmentInput(t *testing.T) {
+	spec := &EnvironmentSpec{
+		Name:       "analytics",
+		MaxWorkers: aws.Int32(20),
+		NetworkConfiguration: &NetworkSpec{
+			SecurityGroupIDs: []string{"sg-b"},
+		},
+	}
+
+	input := spec.UpdateEnvironmentInput()
+	assert.Equal(t, aws.Int32(20), input.MaxWorkers)
+	assert.Equal(t, []string{"sg-b"}, input.NetworkConfiguration.SecurityGroupIds)
+	assert.Nil(t, input.LoggingConfiguration)
+
+	spec, err := ParseEnvironmentSpec(strings.NewReader(testEnvironmentSpec))
+	assert.NoError(t, err)
+
+	input = spec.UpdateEnvironmentInput()
+	assert.Equal(t, "analytics", aws.ToString(input.Name))
+	assert.Equal(t, []string{"sg-a"}, input.NetworkConfiguration.SecurityGroupIds)
+	assert.Equal(t, aws.String("requirements.txt"), input.RequirementsS3Path)
+}
+
+func TestNewEnvironmentSpec(t *testing.T) {
+	spec := NewEnvironmentSpec(&types.Environment{
+		Name:                        aws.String("analytics"),
+		Arn:                         aws.String("arn:aws:airflow:eu-west-1:123456789012:environment/analytics"),
+		Status:                      types.EnvironmentStatusAvailable,
+		MaxWorkers:                  aws.Int32(10),
+		RequirementsS3ObjectVersion: aws.String("v2"),
+		WebserverAccessMode:         types.WebserverAccessModePublicOnly,
+		NetworkConfiguration: &types.NetworkConfiguration{
+			SubnetIds:        []string{"subnet-a"},
+			SecurityGroupIds: []string{"sg-a"},
+		},
+		LoggingConfiguration: &types.LoggingConfiguration{
+			TaskLogs: &types.ModuleLoggingConfiguration{
+				CloudWatchLogGroupArn: aws.String("arn:aws:logs:eu-west-1:123456789012:log-group:airflow-analytics-Task"),
+				Enabled:               aws.Bool(true),
+				LogLevel:              types.LoggingLevelInfo,
+			},
+		},
+	})
+
+	assert.Equal(t, &EnvironmentSpec{
+		Name:                        "analytics",
+		MaxWorkers:                  aws.Int32(10),
+		RequirementsS3ObjectVersion: aws.String("v2"),
+		WebserverAccessMode:         "PUBLIC_ONLY",
+		NetworkConfiguration: &NetworkSpec{
+			SubnetIDs:        []string{"subnet-a"},
+			SecurityGroupIDs: []string{"sg-a"},
+		},
+		LoggingConfiguration: &LoggingSpec{
+			TaskLogs: &ModuleLoggingSpec{Enabled: aws.Bool(true), LogLevel: "INFO"},
+		},
+	}, spec)
+}
+
+func TestEnvironmentSpecTemplatize(t *testing.T) {
+	spec := &EnvironmentSpec{
+		Name:                        "analytics",
+		ExecutionRoleArn:            aws.String("arn:aws:iam::123456789012:role/mwaa"),
+		SourceBucketArn:             aws.String("arn:aws:s3:::mwaa-bucket"),
+		RequirementsS3Path:          aws.String("requirements.txt"),
+		RequirementsS3ObjectVersion: aws.String("v2"),
+		NetworkConfiguration: &NetworkSpec{
+			SubnetIDs:        []string{"subnet-a", "subnet-b"},
+			SecurityGroupIDs: []string{"sg-a"},
+		},
+	}
+
+	spec.Templatize()
+
+	assert.Equal(t, aws.String("${EXECUTION_ROLE_ARN}"), spec.ExecutionRoleArn)
+	assert.Equal(t, aws.String("${SOURCE_BUCKET_ARN}"), spec.SourceBucketArn)
+	assert.Nil(t, spec.KmsKey)
+	assert.Equal(t, aws.String("requirements.txt"), spec.RequirementsS3Path)
+	assert.Nil(t, spec.RequirementsS3ObjectVersion)
+	assert.Equal(t, []string{"${SUBNET_ID_1}", "${SUBNET_ID_2}"}, spec.NetworkConfiguration.SubnetIDs)
+	assert.Equal(t, []string{"${SECURITY_GROUP_ID_1}"}, spec.NetworkConfiguration.SecurityGroupIDs)
+}
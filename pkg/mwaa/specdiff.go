@@ -0,0 +1,155 @@
+package mwaa
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// SpecChangeAction describes how a field of an environment spec changes.
+type SpecChangeAction string
+
+const (
+	SpecChangeAdd    SpecChangeAction = "+" // The field is set in the desired spec only.
+	SpecChangeUpdate SpecChangeAction = "~" // The field is set in both specs with different values.
+	SpecChangeRemove SpecChangeAction = "-" // The field is set in the current spec only.
+)
+
+// SpecChange is a single difference between two environment specs.
+type SpecChange struct {
+	Key       string           // The flattened key of the field, e.g. "airflowConfigurationOptions.core.default_timezone".
+	Action    SpecChangeAction // The kind of change.
+	Current   string           // The current value, empty for additions.
+	Desired   string           // The desired value, empty for removals.
+	Immutable bool             // Whether the field can only be set when the environment is created.
+}
+
+// mapSections are the spec fields whose keys are replaced as a whole, so keys missing
+// from the desired spec are removed.
+var mapSections = map[string]bool{
+	"airflowConfigurationOptions": true,
+	"tags":                        true,
+}
+
+// immutableKeys are the spec fields that can only be set when an environment is created.
+var immutableKeys = map[string]bool{
+	"kmsKey":                         true,
+	"endpointManagement":             true,
+	"networkConfiguration.subnetIds": true,
+	"tags":                           true,
+}
+
+// Flatten converts the spec to a map of dotted keys to values.
+// List values are sorted and joined by commas. Unset fields are omitted.
+func (s *EnvironmentSpec) Flatten() (map[string]string, error) {
+	data, err := json.Marshal(s)
+	if err != nil {
+		return nil, fmt.Errorf("failed to serialize environment spec: %w", err)
+	}
+
+	var raw map[string]any
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, fmt.Errorf("failed to serialize environment spec: %w", err)
+	}
+
+	flat := map[string]string{}
+	flattenValue(flat, "", raw)
+
+	return flat, nil
+}
+
+// flattenValue adds the value to the flat map under the given key, descending into nested objects.
+func flattenValue(flat map[string]string, key string, value any) {
+	switch v := value.(type) {
+	case map[string]any:
+		for k, nested := range v {
+			if key != "" {
+				k = key + "." + k
+			}
+
+			flattenValue(flat, k, nested)
+		}
+	case []any:
+		items := make([]string, 0, len(v))
+		for _, item := range v {
+			items = append(items, fmt.Sprint(item))
+		}
+
+		sort.Strings(items)
+
+		flat[key] = strings.Join(items, ", ")
+	default:
+		flat[key] = fmt.Sprint(v)
+	}
+}
+
+// DiffEnvironmentSpecs compares a desired spec with the current one and returns the changes sorted by key.
+// Fields that are not set in the desired spec are left unchanged and therefore not reported, except for keys of
+// Airflow configuration options and tags, which are replaced as a whole. A nil current spec reports every desired
+// field as an addition.
+func DiffEnvironmentSpecs(desired, current *EnvironmentSpec) ([]SpecChange, error) {
+	desiredFlat, err := desired.Flatten()
+	if err != nil {
+		return nil, err
+	}
+
+	currentFlat := map[string]string{}
+
+	if current != nil {
+		currentFlat, err = current.Flatten()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	delete(desiredFlat, "name")
+	delete(currentFlat, "name")
+
+	var changes []SpecChange
+
+	for key, desiredValue := range desiredFlat {
+		currentValue, exists := currentFlat[key]
+
+		switch {
+		case !exists:
+			changes = append(changes, SpecChange{Key: key, Action: SpecChangeAdd, Desired: desiredValue})
+		case currentValue != desiredValue:
+			changes = append(changes, SpecChange{Key: key, Action: SpecChangeUpdate, Current: currentValue, Desired: desiredValue})
+		}
+	}
+
+	for key, currentValue := range currentFlat {
+		if _, exists := desiredFlat[key]; exists {
+			continue
+		}
+
+		section, _, _ := strings.Cut(key, ".")
+		if mapSections[section] && desired.sectionSet(section) {
+			changes = append(changes, SpecChange{Key: key, Action: SpecChangeRemove, Current: currentValue})
+		}
+	}
+
+	for i := range changes {
+		section, _, _ := strings.Cut(changes[i].Key, ".")
+		changes[i].Immutable = current != nil && (immutableKeys[changes[i].Key] || immutableKeys[section])
+	}
+
+	sort.Slice(changes, func(i, j int) bool {
+		return changes[i].Key < changes[j].Key
+	})
+
+	return changes, nil
+}
+
+// sectionSet reports whether the map section of the spec with the given name is set.
+func (s *EnvironmentSpec) sectionSet(section string) bool {
+	switch section {
+	case "airflowConfigurationOptions":
+		return s.AirflowConfigurationOptions != nil
+	case "tags":
+		return s.Tags != nil
+	default:
+		return false
+	}
+}
@@ -0,0 +1,101 @@
+package mwaa
+
+import (
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEnvironmentSpecFlatten(t *testing.T) {
+	spec := &EnvironmentSpec{
+		Name:       "analytics",
+		MaxWorkers: aws.Int32(10),
+		AirflowConfigurationOptions: map[string]string{
+			"core.default_timezone": "utc",
+		},
+		NetworkConfiguration: &NetworkSpec{
+			SecurityGroupIDs: []string{"sg-b", "sg-a"},
+		},
+		LoggingConfiguration: &LoggingSpec{
+			TaskLogs: &ModuleLoggingSpec{Enabled: aws.Bool(true), LogLevel: "INFO"},
+		},
+	}
+
+	flat, err := spec.Flatten()
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]string{
+		"name":       "analytics",
+		"maxWorkers": "10",
+		"airflowConfigurationOptions.core.default_timezone": "utc",
+		"networkConfiguration.securityGroupIds":             "sg-a, sg-b",
+		"loggingConfiguration.taskLogs.enabled":             "true",
+		"loggingConfiguration.taskLogs.logLevel":            "INFO",
+	}, flat)
+}
+
+func TestDiffEnvironmentSpecs(t *testing.T) {
+	current := &EnvironmentSpec{
+		Name:                         "analytics",
+		MaxWorkers:                   aws.Int32(5),
+		MinWorkers:                   aws.Int32(1),
+		RequirementsS3ObjectVersion:  aws.String("v1"),
+		WeeklyMaintenanceWindowStart: aws.String("SUN:03:30"),
+		AirflowConfigurationOptions: map[string]string{
+			"core.default_timezone": "utc",
+			"core.parallelism":      "32",
+		},
+		Tags: map[string]string{"team": "data"},
+		LoggingConfiguration: &LoggingSpec{
+			TaskLogs:      &ModuleLoggingSpec{Enabled: aws.Bool(true), LogLevel: "INFO"},
+			SchedulerLogs: &ModuleLoggingSpec{Enabled: aws.Bool(true), LogLevel: "WARNING"},
+		},
+	}
+
+	t.Run("Changes", func(t *testing.T) {
+		desired := &EnvironmentSpec{
+			Name:                         "analytics",
+			MaxWorkers:                   aws.Int32(10),
+			RequirementsS3ObjectVersion:  aws.String("v2"),
+			WeeklyMaintenanceWindowStart: aws.String("SUN:03:30"),
+			AirflowConfigurationOptions: map[string]string{
+				"core.default_timezone":   "utc",
+				"celery.worker_autoscale": "5,5",
+			},
+			Tags: map[string]string{"team": "platform"},
+			LoggingConfiguration: &LoggingSpec{
+				TaskLogs: &ModuleLoggingSpec{Enabled: aws.Bool(true), LogLevel: "DEBUG"},
+			},
+		}
+
+		changes, err := DiffEnvironmentSpecs(desired, current)
+		assert.NoError(t, err)
+		assert.Equal(t, []SpecChange{
+			{Key: "airflowConfigurationOptions.celery.worker_autoscale", Action: SpecChangeAdd, Desired: "5,5"},
+			{Key: "airflowConfigurationOptions.core.parallelism", Action: SpecChangeRemove, Current: "32"},
+			{Key: "loggingConfiguration.taskLogs.logLevel", Action: SpecChangeUpdate, Current: "INFO", Desired: "DEBUG"},
+			{Key: "maxWorkers", Action: SpecChangeUpdate, Current: "5", Desired: "10"},
+			{Key: "requirementsS3ObjectVersion", Action: SpecChangeUpdate, Current: "v1", Desired: "v2"},
+			{Key: "tags.team", Action: SpecChangeUpdate, Current: "data", Desired: "platform", Immutable: true},
+		}, changes)
+	})
+
+	t.Run("No changes", func(t *testing.T) {
+		desired := &EnvironmentSpec{Name: "analytics", MaxWorkers: aws.Int32(5)}
+
+		changes, err := DiffEnvironmentSpecs(desired, current)
+		assert.NoError(t, err)
+		assert.Empty(t, changes)
+	})
+
+	t.Run("New environment", func(t *testing.T) {
+		desired := &EnvironmentSpec{Name: "analytics", MaxWorkers: aws.Int32(5), KmsKey: aws.String("key")}
+
+		changes, err := DiffEnvironmentSpecs(desired, nil)
+		assert.NoError(t, err)
+		assert.Equal(t, []SpecChange{
+			{Key: "kmsKey", Action: SpecChangeAdd, Desired: "key"},
+			{Key: "maxWorkers", Action: SpecChangeAdd, Desired: "5"},
+		}, changes)
+	})
+}
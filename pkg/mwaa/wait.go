@@ -0,0 +1,148 @@
+package mwaa
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/mwaa/types"
+)
+
+// WaitForEnvironmentOptions defines the options for WaitForEnvironmentStatus.
+type WaitForEnvironmentOptions struct {
+	// MinDelay is the delay before the second poll. It doubles after every poll.
+	MinDelay time.Duration
+
+	// MaxDelay is the upper bound of the delay between two polls.
+	MaxDelay time.Duration
+
+	// OnStatus is called with the status observed by every poll.
+	OnStatus func(status types.EnvironmentStatus)
+}
+
+// EnvironmentFailedError is returned when an environment ends in a failed status while waiting.
+type EnvironmentFailedError struct {
+	Environment *types.Environment // The environment as returned by the last poll.
+}
+
+// Error implements the error interface.
+func (e *EnvironmentFailedError) Error() string {
+	msg := fmt.Sprintf("environment %s ended in status %s", aws.ToString(e.Environment.Name), e.Environment.Status)
+
+	if update := e.Environment.LastUpdate; update != nil && update.Error != nil {
+		msg = fmt.Sprintf("%s: %s: %s", msg, aws.ToString(update.Error.ErrorCode), aws.ToString(update.Error.ErrorMessage))
+	}
+
+	return msg
+}
+
+// WaitForEnvironmentStatus polls an MWAA environment with exponential backoff until it reaches the target status.
+// A deleted environment is reported as types.EnvironmentStatusDeleted. An EnvironmentFailedError is returned if the
+// environment ends in CREATE_FAILED or UPDATE_FAILED, or is deleted, while waiting for another status.
+func (c *Client) WaitForEnvironmentStatus(ctx context.Context, environmentName string, target types.EnvironmentStatus, optFns ...func(o *WaitForEnvironmentOptions)) (*types.Environment, error) {
+	return waitForEnvironmentStatus(ctx, c.environmentGetter(environmentName), target, optFns...)
+}
+
+// environmentGetter returns a getter for the environment that reports a deleted environment as
+// types.EnvironmentStatusDeleted instead of an error.
+func (c *Client) environmentGetter(environmentName string) func(ctx context.Context) (*types.Environment, error) {
+	return func(ctx context.Context) (*types.Environment, error) {
+		environment, err := c.GetEnvironment(ctx, environmentName)
+		if err != nil {
+			var notFoundErr *types.ResourceNotFoundException
+			if errors.As(err, &notFoundErr) {
+				return &types.Environment{Name: aws.String(environmentName), Status: types.EnvironmentStatusDeleted}, nil
+			}
+
+			return nil, err
+		}
+
+		return environment, nil
+	}
+}
+
+// waitForEnvironmentStatus implements WaitForEnvironmentStatus on top of the given environment getter.
+func waitForEnvironmentStatus(ctx context.Context, getEnvironment func(ctx context.Context) (*types.Environment, error), target types.EnvironmentStatus, optFns ...func(o *WaitForEnvironmentOptions)) (*types.Environment, error) {
+	return pollEnvironment(ctx, getEnvironment, func(environment *types.Environment) (bool, error) {
+		switch environment.Status {
+		case target:
+			return true, nil
+		case types.EnvironmentStatusCreateFailed, types.EnvironmentStatusUpdateFailed, types.EnvironmentStatusDeleted:
+			return true, &EnvironmentFailedError{Environment: environment}
+		default:
+			return false, nil
+		}
+	}, optFns...)
+}
+
+// WaitForEnvironmentUpdate polls an MWAA environment with exponential backoff until an update that was created
+// after the given time has finished and the environment is AVAILABLE again. The time is the CreatedAt of the
+// LastUpdate before the update was requested, or nil if the environment has never been updated.
+// Unlike waiting for a status, this does not miss updates that start and finish between two polls.
+// An EnvironmentFailedError is returned if the update fails or the environment is deleted.
+func (c *Client) WaitForEnvironmentUpdate(ctx context.Context, environmentName string, previousUpdate *time.Time, optFns ...func(o *WaitForEnvironmentOptions)) (*types.Environment, error) {
+	return waitForEnvironmentUpdate(ctx, c.environmentGetter(environmentName), previousUpdate, optFns...)
+}
+
+// waitForEnvironmentUpdate implements WaitForEnvironmentUpdate on top of the given environment getter.
+func waitForEnvironmentUpdate(ctx context.Context, getEnvironment func(ctx context.Context) (*types.Environment, error), previousUpdate *time.Time, optFns ...func(o *WaitForEnvironmentOptions)) (*types.Environment, error) {
+	return pollEnvironment(ctx, getEnvironment, func(environment *types.Environment) (bool, error) {
+		if environment.Status == types.EnvironmentStatusDeleted {
+			return true, &EnvironmentFailedError{Environment: environment}
+		}
+
+		update := environment.LastUpdate
+		if update == nil || update.CreatedAt == nil || (previousUpdate != nil && !update.CreatedAt.After(*previousUpdate)) {
+			// The update has not been registered yet
+			return false, nil
+		}
+
+		switch update.Status {
+		case types.UpdateStatusSuccess:
+			return environment.Status == types.EnvironmentStatusAvailable, nil
+		case types.UpdateStatusFailed:
+			return true, &EnvironmentFailedError{Environment: environment}
+		default:
+			return false, nil
+		}
+	}, optFns...)
+}
+
+// pollEnvironment polls an environment with exponential backoff until done reports true or returns an error.
+func pollEnvironment(ctx context.Context, getEnvironment func(ctx context.Context) (*types.Environment, error), done func(environment *types.Environment) (bool, error), optFns ...func(o *WaitForEnvironmentOptions)) (*types.Environment, error) {
+	opts := WaitForEnvironmentOptions{
+		MinDelay: 10 * time.Second,
+		MaxDelay: time.Minute,
+	}
+
+	for _, fn := range optFns {
+		fn(&opts)
+	}
+
+	delay := opts.MinDelay
+
+	for {
+		environment, err := getEnvironment(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		if opts.OnStatus != nil {
+			opts.OnStatus(environment.Status)
+		}
+
+		if ok, err := done(environment); ok || err != nil {
+			return environment, err
+		}
+
+		select {
+		case <-ctx.Done():
+			return environment, ctx.Err()
+		case <-time.After(delay):
+		}
+
+		delay = min(delay*2, opts.MaxDelay)
+	}
+}
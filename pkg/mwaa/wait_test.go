@@ -0,0 +1,163 @@
+package mwaa
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/mwaa/types"
+	"github.com/stretchr/testify/assert"
+)
+
+// environmentSequence returns a getter that yields the given statuses in order, repeating the last one.
+func environmentSequence(statuses ...types.EnvironmentStatus) func(ctx context.Context) (*types.Environment, error) {
+	i := 0
+
+	return func(_ context.Context) (*types.Environment, error) {
+		status := statuses[min(i, len(statuses)-1)]
+		i++
+
+		environment := &types.Environment{Name: aws.String("analytics"), Status: status}
+
+		if status == types.EnvironmentStatusUpdateFailed {
+			environment.LastUpdate = &types.LastUpdate{
+				Status: types.UpdateStatusFailed,
+				Error: &types.UpdateError{
+					ErrorCode:    aws.String("INCORRECT_CONFIGURATION"),
+					ErrorMessage: aws.String("invalid requirements"),
+				},
+			}
+		}
+
+		return environment, nil
+	}
+}
+
+func withShortDelay(o *WaitForEnvironmentOptions) {
+	o.MinDelay = time.Millisecond
+	o.MaxDelay = 2 * time.Millisecond
+}
+
+func TestWaitForEnvironmentStatus(t *testing.T) {
+	t.Run("Reaches target", func(t *testing.T) {
+		var observed []types.EnvironmentStatus
+
+		get := environmentSequence(types.EnvironmentStatusUpdating, types.EnvironmentStatusUpdating, types.EnvironmentStatusAvailable)
+
+		environment, err := waitForEnvironmentStatus(context.Background(), get, types.EnvironmentStatusAvailable, withShortDelay, func(o *WaitForEnvironmentOptions) {
+			o.OnStatus = func(status types.EnvironmentStatus) {
+				observed = append(observed, status)
+			}
+		})
+
+		assert.NoError(t, err)
+		assert.Equal(t, types.EnvironmentStatusAvailable, environment.Status)
+		assert.Equal(t, []types.EnvironmentStatus{
+			types.EnvironmentStatusUpdating,
+			types.EnvironmentStatusUpdating,
+			types.EnvironmentStatusAvailable,
+		}, observed)
+	})
+
+	t.Run("Update failed", func(t *testing.T) {
+		get := environmentSequence(types.EnvironmentStatusUpdating, types.EnvironmentStatusUpdateFailed)
+
+		_, err := waitForEnvironmentStatus(context.Background(), get, types.EnvironmentStatusAvailable, withShortDelay)
+
+		var failedErr *EnvironmentFailedError
+		assert.ErrorAs(t, err, &failedErr)
+		assert.EqualError(t, err, "environment analytics ended in status UPDATE_FAILED: INCORRECT_CONFIGURATION: invalid requirements")
+	})
+
+	t.Run("Timeout", func(t *testing.T) {
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		defer cancel()
+
+		get := environmentSequence(types.EnvironmentStatusUpdating)
+
+		environment, err := waitForEnvironmentStatus(ctx, get, types.EnvironmentStatusAvailable, withShortDelay)
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+		assert.Equal(t, types.EnvironmentStatusUpdating, environment.Status)
+	})
+}
+
+func TestWaitForEnvironmentUpdate(t *testing.T) {
+	previous := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	requested := previous.Add(24 * time.Hour)
+
+	// sequence returns a getter that yields environments with the given status and last update in order,
+	// repeating the last one.
+	sequence := func(environments ...*types.Environment) func(ctx context.Context) (*types.Environment, error) {
+		i := 0
+
+		return func(_ context.Context) (*types.Environment, error) {
+			environment := environments[min(i, len(environments)-1)]
+			i++
+
+			return environment, nil
+		}
+	}
+
+	environment := func(status types.EnvironmentStatus, createdAt time.Time, updateStatus types.UpdateStatus) *types.Environment {
+		return &types.Environment{
+			Name:       aws.String("analytics"),
+			Status:     status,
+			LastUpdate: &types.LastUpdate{CreatedAt: aws.Time(createdAt), Status: updateStatus},
+		}
+	}
+
+	t.Run("Update finished between polls", func(t *testing.T) {
+		get := sequence(
+			environment(types.EnvironmentStatusAvailable, previous, types.UpdateStatusSuccess),
+			environment(types.EnvironmentStatusAvailable, requested, types.UpdateStatusSuccess),
+		)
+
+		result, err := waitForEnvironmentUpdate(context.Background(), get, aws.Time(previous), withShortDelay)
+		assert.NoError(t, err)
+		assert.Equal(t, requested, aws.ToTime(result.LastUpdate.CreatedAt))
+	})
+
+	t.Run("Update in progress", func(t *testing.T) {
+		var observed []types.EnvironmentStatus
+
+		get := sequence(
+			environment(types.EnvironmentStatusUpdating, requested, types.UpdateStatusPending),
+			environment(types.EnvironmentStatusAvailable, requested, types.UpdateStatusSuccess),
+		)
+
+		_, err := waitForEnvironmentUpdate(context.Background(), get, aws.Time(previous), withShortDelay, func(o *WaitForEnvironmentOptions) {
+			o.OnStatus = func(status types.EnvironmentStatus) {
+				observed = append(observed, status)
+			}
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, []types.EnvironmentStatus{types.EnvironmentStatusUpdating, types.EnvironmentStatusAvailable}, observed)
+	})
+
+	t.Run("First update", func(t *testing.T) {
+		get := sequence(environment(types.EnvironmentStatusAvailable, requested, types.UpdateStatusSuccess))
+
+		_, err := waitForEnvironmentUpdate(context.Background(), get, nil, withShortDelay)
+		assert.NoError(t, err)
+	})
+
+	t.Run("Update failed and rolled back", func(t *testing.T) {
+		get := sequence(environment(types.EnvironmentStatusAvailable, requested, types.UpdateStatusFailed))
+
+		_, err := waitForEnvironmentUpdate(context.Background(), get, aws.Time(previous), withShortDelay)
+
+		var failedErr *EnvironmentFailedError
+		assert.ErrorAs(t, err, &failedErr)
+	})
+
+	t.Run("No new update", func(t *testing.T) {
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		defer cancel()
+
+		get := sequence(environment(types.EnvironmentStatusAvailable, previous, types.UpdateStatusSuccess))
+
+		_, err := waitForEnvironmentUpdate(ctx, get, aws.Time(previous), withShortDelay)
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+	})
+}
@@ -112,106 +112,118 @@ func (s *Client) DownloadAndUnzip(ctx context.Context, input *DownloadAndUnzipIn
 	return nil
 }
 
+// ReadObjectInput defines the input parameters for the ReadObject method.
+type ReadObjectInput struct {
+	Bucket  *string // S3 bucket name
+	Key     *string // S3 object key
+	Version *string // Optional S3 object version
+}
+
+// ReadObject downloads an S3 object into memory.
+func (s *Client) ReadObject(ctx context.Context, input *ReadObjectInput) ([]byte, error) {
+	if input.Bucket == nil || input.Key == nil {
+		return nil, fmt.Errorf("bucket and key are required")
+	}
+
+	output, err := s.client.GetObject(ctx, &s3.GetObjectInput{
+		Bucket:    input.Bucket,
+		Key:       input.Key,
+		VersionId: input.Version,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to download file from S3: %w", err)
+	}
+	defer output.Body.Close()
+
+	data, err := io.ReadAll(output.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read S3 object: %w", err)
+	}
+
+	return data, nil
+}
+
 // SyncDirectoryInput defines the input parameters for the SyncDirectory method.
 type SyncDirectoryInput struct {
-	Bucket   *string // S3 bucket name
-	Prefix   *string // S3 prefix for the directory
-	LocalDir *string // Local directory to sync files to
+	Bucket   *string                   // S3 bucket name
+	Prefix   *string                   // S3 prefix for the directory
+	LocalDir *string                   // Local directory to sync files to
+	Filter   func(relPath string) bool // Optional filter that selects files by their slash-separated relative path
 }
 
-// SyncDirectory synchronizes files from an S3 bucket to a local directory.
+// SyncDirectory synchronizes files from an S3 prefix to a local directory. Files are only downloaded if their MD5
+// checksum differs from the ETag of the remote object, and local files that do not exist remotely are deleted.
+// Files rejected by the filter are neither downloaded nor deleted.
 func (s *Client) SyncDirectory(ctx context.Context, input *SyncDirectoryInput) error {
 	if input.Bucket == nil || input.Prefix == nil || input.LocalDir == nil {
 		return fmt.Errorf("bucket, prefix, and localDir are required")
 	}
 
-	// List objects in the S3 bucket
-	listOutput, err := s.client.ListObjectsV2(ctx, &s3.ListObjectsV2Input{
-		Bucket: input.Bucket,
-		Prefix: input.Prefix,
-	})
-	if err != nil {
-		return fmt.Errorf("failed to list objects in S3 bucket: %w", err)
+	filter := input.Filter
+	if filter == nil {
+		filter = func(string) bool { return true }
 	}
 
-	// Create a map of S3 objects for comparison
-	s3Objects := make(map[string]types.Object)
-
-	for _, obj := range listOutput.Contents {
-		relativePath := strings.TrimPrefix(aws.ToString(obj.Key), aws.ToString(input.Prefix))
-		s3Objects[relativePath] = obj
-	}
+	prefix := normalizePrefix(aws.ToString(input.Prefix))
+	localDir := aws.ToString(input.LocalDir)
 
 	// Ensure the local directory exists
-	if err := os.MkdirAll(*input.LocalDir, 0755); err != nil {
+	if err := os.MkdirAll(localDir, 0755); err != nil {
 		return fmt.Errorf("failed to create local directory: %w", err)
 	}
 
-	// Download files from S3
-	for relativePath, obj := range s3Objects {
-		localFilePath := filepath.Join(*input.LocalDir, relativePath)
-
-		// Create parent directories if necessary
-		if err := os.MkdirAll(filepath.Dir(localFilePath), 0755); err != nil {
-			return fmt.Errorf("failed to create directories for %s: %w", localFilePath, err)
-		}
-
-		// Download the file
-		getOutput, err := s.client.GetObject(ctx, &s3.GetObjectInput{
-			Bucket: input.Bucket,
-			Key:    obj.Key,
-		})
-		if err != nil {
-			return fmt.Errorf("failed to download %s: %w", *obj.Key, err)
-		}
-		defer getOutput.Body.Close()
+	localFiles, err := listLocalFiles(localDir, filter)
+	if err != nil {
+		return err
+	}
 
-		// Write the file to the local directory
-		localFile, err := os.Create(localFilePath)
-		if err != nil {
-			return fmt.Errorf("failed to create local file %s: %w", localFilePath, err)
-		}
-		defer localFile.Close()
+	// Create a map of S3 objects for comparison
+	s3Objects, err := s.listObjects(ctx, aws.ToString(input.Bucket), prefix, filter)
+	if err != nil {
+		return err
+	}
 
-		if _, err := io.Copy(localFile, getOutput.Body); err != nil {
-			return fmt.Errorf("failed to write to local file %s: %w", localFilePath, err)
+	// Download new and changed files from S3
+	for relativePath, obj := range s3Objects {
+		if file, exists := localFiles[relativePath]; exists && strings.Trim(aws.ToString(obj.ETag), `"`) == file.md5 {
+			continue
 		}
 
-		// Set the file's modification time to match the S3 object's LastModified
-		if err := os.Chtimes(localFilePath, aws.ToTime(obj.LastModified), aws.ToTime(obj.LastModified)); err != nil {
-			return fmt.Errorf("failed to set timestamp for %s: %w", localFilePath, err)
+		if err := s.downloadObject(ctx, aws.ToString(input.Bucket), obj, filepath.Join(localDir, filepath.FromSlash(relativePath))); err != nil {
+			return err
 		}
 	}
 
 	// Delete local files not present in the S3 bucket
-	err = filepath.Walk(aws.ToString(input.LocalDir), func(path string, info os.FileInfo, err error) error {
-		if err != nil {
-			return err
+	for relativePath, file := range localFiles {
+		if _, exists := s3Objects[relativePath]; !exists {
+			if err := os.Remove(file.path); err != nil {
+				return fmt.Errorf("failed to delete local file %s: %w", file.path, err)
+			}
 		}
+	}
 
-		// Skip directories
-		if info.IsDir() {
-			return nil
-		}
+	return nil
+}
 
-		// Get the relative path of the local file
-		relativePath, err := filepath.Rel(aws.ToString(input.LocalDir), path)
-		if err != nil {
-			return err
-		}
+// downloadObject downloads an S3 object to a local file and sets the file's modification time
+// to match the object's LastModified.
+func (s *Client) downloadObject(ctx context.Context, bucket string, obj types.Object, localFilePath string) error {
+	// Create parent directories if necessary
+	if err := os.MkdirAll(filepath.Dir(localFilePath), 0755); err != nil {
+		return fmt.Errorf("failed to create directories for %s: %w", localFilePath, err)
+	}
 
-		// Check if the file exists in the S3 bucket
-		if _, exists := s3Objects[relativePath]; !exists {
-			// Delete the file if it doesn't exist in the S3 bucket
-			if err := os.Remove(path); err != nil {
-				return fmt.Errorf("failed to delete local file %s: %w", path, err)
-			}
-		}
+	if err := s.DownloadFile(ctx, &DownloadFileInput{
+		Bucket:    aws.String(bucket),
+		Key:       obj.Key,
+		LocalPath: aws.String(localFilePath),
+	}); err != nil {
+		return fmt.Errorf("failed to download %s: %w", aws.ToString(obj.Key), err)
+	}
 
-		return nil
-	})
-	if err != nil {
-		return fmt.Errorf("failed to clean up local files: %w", err)
+	if err := os.Chtimes(localFilePath, aws.ToTime(obj.LastModified), aws.ToTime(obj.LastModified)); err != nil {
+		return fmt.Errorf("failed to set timestamp for %s: %w", localFilePath, err)
 	}
 
 	return nil
@@ -0,0 +1,327 @@
+package s3
+
+import (
+	"context"
+	"crypto/md5"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/aws/arn"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
+)
+
+// BucketNameFromARN extracts the bucket name from an S3 bucket ARN, e.g. "arn:aws:s3:::my-bucket".
+func BucketNameFromARN(bucketArn string) (string, error) {
+	parsed, err := arn.Parse(bucketArn)
+	if err != nil {
+		return "", fmt.Errorf("invalid bucket ARN %q: %w", bucketArn, err)
+	}
+
+	if parsed.Service != "s3" || parsed.Resource == "" || strings.Contains(parsed.Resource, "/") {
+		return "", fmt.Errorf("invalid bucket ARN %q", bucketArn)
+	}
+
+	return parsed.Resource, nil
+}
+
+// SyncAction describes what an upload plan does with an S3 object.
+type SyncAction string
+
+const (
+	SyncActionUpload SyncAction = "upload" // The local file is uploaded.
+	SyncActionDelete SyncAction = "delete" // The remote object is deleted.
+)
+
+// SyncOperation is a single step of an upload plan.
+type SyncOperation struct {
+	Action    SyncAction // The action to perform.
+	Key       string     // The S3 object key.
+	LocalPath string     // The local file to upload, empty for deletions.
+	Reason    string     // Why the operation is needed: "new", "changed" or "removed".
+}
+
+// UploadPlan lists the operations needed to sync a local directory to an S3 prefix.
+type UploadPlan struct {
+	Bucket     string          // S3 bucket name
+	Operations []SyncOperation // The operations sorted by key.
+	Unchanged  int             // The number of files that are already up to date.
+}
+
+// PlanUploadDirectoryInput defines the input parameters for the PlanUploadDirectory method.
+type PlanUploadDirectoryInput struct {
+	Bucket   *string                   // S3 bucket name
+	Prefix   *string                   // S3 prefix for the directory (e.g., "dags")
+	LocalDir *string                   // Local directory to upload files from
+	Filter   func(relPath string) bool // Optional filter that selects files by their slash-separated relative path
+	Delete   bool                      // Delete remote objects that do not exist locally
+}
+
+// localFile is a file of the local directory that is a candidate for upload.
+type localFile struct {
+	path string // The local file path.
+	md5  string // The hex-encoded MD5 checksum of the content.
+}
+
+// PlanUploadDirectory compares a local directory with an S3 prefix and returns the operations needed to sync it.
+// Files are uploaded if they are new or their MD5 checksum differs from the ETag of the remote object.
+// Objects that were uploaded in multiple parts have no MD5 ETag and are always uploaded again.
+// Remote objects rejected by the filter are never deleted.
+func (s *Client) PlanUploadDirectory(ctx context.Context, input *PlanUploadDirectoryInput) (*UploadPlan, error) {
+	if input.Bucket == nil || input.Prefix == nil || input.LocalDir == nil {
+		return nil, fmt.Errorf("bucket, prefix, and localDir are required")
+	}
+
+	filter := input.Filter
+	if filter == nil {
+		filter = func(string) bool { return true }
+	}
+
+	prefix := normalizePrefix(aws.ToString(input.Prefix))
+
+	localFiles, err := listLocalFiles(aws.ToString(input.LocalDir), filter)
+	if err != nil {
+		return nil, err
+	}
+
+	remoteObjects, err := s.listObjects(ctx, aws.ToString(input.Bucket), prefix, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	plan := planUpload(prefix, localFiles, remoteObjects, input.Delete)
+	plan.Bucket = aws.ToString(input.Bucket)
+
+	return plan, nil
+}
+
+// listObjects returns the objects under the normalized prefix selected by the filter, keyed by slash-separated
+// path relative to the prefix.
+func (s *Client) listObjects(ctx context.Context, bucket, prefix string, filter func(relPath string) bool) (map[string]types.Object, error) {
+	objects := make(map[string]types.Object)
+
+	paginator := s3.NewListObjectsV2Paginator(s.client, &s3.ListObjectsV2Input{
+		Bucket: aws.String(bucket),
+		Prefix: aws.String(prefix),
+	})
+
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list objects in S3 bucket: %w", err)
+		}
+
+		for _, obj := range page.Contents {
+			relPath := strings.TrimPrefix(aws.ToString(obj.Key), prefix)
+
+			// Skip folder markers created by the console
+			if relPath == "" || strings.HasSuffix(relPath, "/") || !filter(relPath) {
+				continue
+			}
+
+			objects[relPath] = obj
+		}
+	}
+
+	return objects, nil
+}
+
+// ListChecksumsInput defines the input parameters for the ListChecksums method.
+type ListChecksumsInput struct {
+	Bucket *string                   // S3 bucket name
+	Prefix *string                   // S3 prefix of the directory (e.g., "dags")
+	Filter func(relPath string) bool // Optional filter that selects objects by their slash-separated relative path
+}
+
+// ListChecksums returns the ETags of the objects under a prefix, keyed by slash-separated path relative to the prefix.
+// The ETag is the hex-encoded MD5 checksum of the content, unless the object was uploaded in multiple parts.
+func (s *Client) ListChecksums(ctx context.Context, input *ListChecksumsInput) (map[string]string, error) {
+	if input.Bucket == nil || input.Prefix == nil {
+		return nil, fmt.Errorf("bucket and prefix are required")
+	}
+
+	filter := input.Filter
+	if filter == nil {
+		filter = func(string) bool { return true }
+	}
+
+	objects, err := s.listObjects(ctx, aws.ToString(input.Bucket), normalizePrefix(aws.ToString(input.Prefix)), filter)
+	if err != nil {
+		return nil, err
+	}
+
+	checksums := make(map[string]string, len(objects))
+	for relPath, obj := range objects {
+		checksums[relPath] = strings.Trim(aws.ToString(obj.ETag), `"`)
+	}
+
+	return checksums, nil
+}
+
+// normalizePrefix ensures that a non-empty prefix ends with a slash.
+func normalizePrefix(prefix string) string {
+	prefix = strings.TrimPrefix(prefix, "/")
+	if prefix == "" || strings.HasSuffix(prefix, "/") {
+		return prefix
+	}
+
+	return prefix + "/"
+}
+
+// listLocalFiles returns the files of the directory selected by the filter, keyed by slash-separated relative path.
+func listLocalFiles(dir string, filter func(relPath string) bool) (map[string]localFile, error) {
+	files := make(map[string]localFile)
+
+	err := filepath.WalkDir(dir, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if d.IsDir() {
+			return nil
+		}
+
+		relPath, err := filepath.Rel(dir, p)
+		if err != nil {
+			return err
+		}
+
+		relPath = filepath.ToSlash(relPath)
+		if !filter(relPath) {
+			return nil
+		}
+
+		checksum, err := fileMD5(p)
+		if err != nil {
+			return err
+		}
+
+		files[relPath] = localFile{path: p, md5: checksum}
+
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to read local directory %s: %w", dir, err)
+	}
+
+	return files, nil
+}
+
+// fileMD5 returns the hex-encoded MD5 checksum of a file.
+func fileMD5(filePath string) (string, error) {
+	file, err := os.Open(filePath)
+	if err != nil {
+		return "", err
+	}
+	defer file.Close()
+
+	hash := md5.New() //nolint:gosec // S3 ETags are MD5 checksums
+	if _, err := io.Copy(hash, file); err != nil {
+		return "", err
+	}
+
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
+
+// planUpload computes the operations needed to make the remote objects match the local files.
+func planUpload(prefix string, localFiles map[string]localFile, remoteObjects map[string]types.Object, deleteRemote bool) *UploadPlan {
+	plan := &UploadPlan{}
+
+	for relPath, file := range localFiles {
+		obj, exists := remoteObjects[relPath]
+
+		switch {
+		case !exists:
+			plan.Operations = append(plan.Operations, SyncOperation{Action: SyncActionUpload, Key: prefix + relPath, LocalPath: file.path, Reason: "new"})
+		case strings.Trim(aws.ToString(obj.ETag), `"`) != file.md5:
+			plan.Operations = append(plan.Operations, SyncOperation{Action: SyncActionUpload, Key: prefix + relPath, LocalPath: file.path, Reason: "changed"})
+		default:
+			plan.Unchanged++
+		}
+	}
+
+	if deleteRemote {
+		for relPath, obj := range remoteObjects {
+			if _, exists := localFiles[relPath]; !exists {
+				plan.Operations = append(plan.Operations, SyncOperation{Action: SyncActionDelete, Key: aws.ToString(obj.Key), Reason: "removed"})
+			}
+		}
+	}
+
+	sort.Slice(plan.Operations, func(i, j int) bool {
+		return plan.Operations[i].Key < plan.Operations[j].Key
+	})
+
+	return plan
+}
+
+// ApplyUploadPlan performs the operations of an upload plan in order.
+// The onOperation callback is optional and called before every operation.
+func (s *Client) ApplyUploadPlan(ctx context.Context, plan *UploadPlan, onOperation func(op SyncOperation)) error {
+	for _, op := range plan.Operations {
+		if onOperation != nil {
+			onOperation(op)
+		}
+
+		switch op.Action {
+		case SyncActionUpload:
+			if _, err := s.UploadFile(ctx, &UploadFileInput{
+				Bucket:    aws.String(plan.Bucket),
+				Key:       aws.String(op.Key),
+				LocalPath: aws.String(op.LocalPath),
+			}); err != nil {
+				return err
+			}
+		case SyncActionDelete:
+			if _, err := s.client.DeleteObject(ctx, &s3.DeleteObjectInput{
+				Bucket: aws.String(plan.Bucket),
+				Key:    aws.String(op.Key),
+			}); err != nil {
+				return fmt.Errorf("failed to delete s3://%s/%s: %w", plan.Bucket, op.Key, err)
+			}
+		default:
+			return fmt.Errorf("unknown sync action %q", op.Action)
+		}
+	}
+
+	return nil
+}
+
+// UploadFileInput defines the input parameters for the UploadFile method.
+type UploadFileInput struct {
+	Bucket    *string // S3 bucket name
+	Key       *string // S3 object key (e.g., "requirements.txt")
+	LocalPath *string // Local file path to upload
+}
+
+// UploadFile uploads a local file to S3 and returns the version ID of the new object,
+// which is empty if versioning is not enabled for the bucket.
+func (s *Client) UploadFile(ctx context.Context, input *UploadFileInput) (string, error) {
+	if input.Bucket == nil || input.Key == nil || input.LocalPath == nil {
+		return "", fmt.Errorf("bucket, key, and localPath are required")
+	}
+
+	file, err := os.Open(aws.ToString(input.LocalPath))
+	if err != nil {
+		return "", fmt.Errorf("failed to open local file '%s': %w", aws.ToString(input.LocalPath), err)
+	}
+	defer file.Close()
+
+	output, err := s.client.PutObject(ctx, &s3.PutObjectInput{
+		Bucket: input.Bucket,
+		Key:    input.Key,
+		Body:   file,
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to upload %s to s3://%s/%s: %w", aws.ToString(input.LocalPath), aws.ToString(input.Bucket), aws.ToString(input.Key), err)
+	}
+
+	return aws.ToString(output.VersionId), nil
+}
@@ -0,0 +1,61 @@
+package s3
+
+import (
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBucketNameFromARN(t *testing.T) {
+	bucket, err := BucketNameFromARN("arn:aws:s3:::my-airflow-bucket")
+	assert.NoError(t, err)
+	assert.Equal(t, "my-airflow-bucket", bucket)
+
+	_, err = BucketNameFromARN("my-airflow-bucket")
+	assert.Error(t, err)
+
+	_, err = BucketNameFromARN("arn:aws:iam::123456789012:role/airflow")
+	assert.Error(t, err)
+}
+
+func TestNormalizePrefix(t *testing.T) {
+	assert.Equal(t, "dags/", normalizePrefix("dags"))
+	assert.Equal(t, "dags/", normalizePrefix("/dags/"))
+	assert.Equal(t, "", normalizePrefix(""))
+}
+
+func TestPlanUpload(t *testing.T) {
+	localFiles := map[string]localFile{
+		"new_dag.py":       {path: "local/new_dag.py", md5: "aaa"},
+		"changed_dag.py":   {path: "local/changed_dag.py", md5: "bbb"},
+		"unchanged_dag.py": {path: "local/unchanged_dag.py", md5: "ccc"},
+		"multipart.zip":    {path: "local/multipart.zip", md5: "ddd"},
+	}
+
+	remoteObjects := map[string]types.Object{
+		"changed_dag.py":   {Key: aws.String("dags/changed_dag.py"), ETag: aws.String(`"000"`)},
+		"unchanged_dag.py": {Key: aws.String("dags/unchanged_dag.py"), ETag: aws.String(`"ccc"`)},
+		"multipart.zip":    {Key: aws.String("dags/multipart.zip"), ETag: aws.String(`"ddd-2"`)},
+		"removed_dag.py":   {Key: aws.String("dags/removed_dag.py"), ETag: aws.String(`"eee"`)},
+	}
+
+	t.Run("Without delete", func(t *testing.T) {
+		plan := planUpload("dags/", localFiles, remoteObjects, false)
+
+		assert.Equal(t, 1, plan.Unchanged)
+		assert.Equal(t, []SyncOperation{
+			{Action: SyncActionUpload, Key: "dags/changed_dag.py", LocalPath: "local/changed_dag.py", Reason: "changed"},
+			{Action: SyncActionUpload, Key: "dags/multipart.zip", LocalPath: "local/multipart.zip", Reason: "changed"},
+			{Action: SyncActionUpload, Key: "dags/new_dag.py", LocalPath: "local/new_dag.py", Reason: "new"},
+		}, plan.Operations)
+	})
+
+	t.Run("With delete", func(t *testing.T) {
+		plan := planUpload("dags/", localFiles, remoteObjects, true)
+
+		assert.Len(t, plan.Operations, 4)
+		assert.Equal(t, SyncOperation{Action: SyncActionDelete, Key: "dags/removed_dag.py", Reason: "removed"}, plan.Operations[3])
+	})
+}
@@ -0,0 +1,66 @@
+package s3
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+)
+
+// ObjectVersion is a version of an S3 object.
+type ObjectVersion struct {
+	VersionID    string    // The version ID.
+	LastModified time.Time // When the version was created.
+	Size         int64     // The size of the version in bytes.
+	IsLatest     bool      // Whether the version is the current version of the object.
+}
+
+// ListObjectVersionsInput defines the input parameters for the ListObjectVersions method.
+type ListObjectVersionsInput struct {
+	Bucket *string // S3 bucket name
+	Key    *string // S3 object key (e.g., "requirements.txt")
+}
+
+// ListObjectVersions returns the versions of an S3 object, newest first. Delete markers are skipped.
+func (s *Client) ListObjectVersions(ctx context.Context, input *ListObjectVersionsInput) ([]ObjectVersion, error) {
+	if input.Bucket == nil || input.Key == nil {
+		return nil, fmt.Errorf("bucket and key are required")
+	}
+
+	var versions []ObjectVersion
+
+	paginator := s3.NewListObjectVersionsPaginator(s.client, &s3.ListObjectVersionsInput{
+		Bucket: input.Bucket,
+		Prefix: input.Key,
+	})
+
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list versions of s3://%s/%s: %w", aws.ToString(input.Bucket), aws.ToString(input.Key), err)
+		}
+
+		for _, version := range page.Versions {
+			// The prefix also matches longer keys, e.g. "requirements.txt.bak"
+			if aws.ToString(version.Key) != aws.ToString(input.Key) {
+				continue
+			}
+
+			versions = append(versions, ObjectVersion{
+				VersionID:    aws.ToString(version.VersionId),
+				LastModified: aws.ToTime(version.LastModified),
+				Size:         aws.ToInt64(version.Size),
+				IsLatest:     aws.ToBool(version.IsLatest),
+			})
+		}
+	}
+
+	sort.SliceStable(versions, func(i, j int) bool {
+		return versions[i].LastModified.After(versions[j].LastModified)
+	})
+
+	return versions, nil
+}
@@ -0,0 +1,150 @@
+package util
+
+import (
+	"fmt"
+	"strings"
+)
+
+// diffContextLines is the number of unchanged lines shown around the changes of a unified diff.
+const diffContextLines = 3
+
+// maxDiffCells limits the size of the table used to compute a diff, to about 10k lines on each side.
+const maxDiffCells = 100_000_000
+
+// diffOp is a single line of an edit script: ' ' keeps, '-' deletes and '+' inserts the line.
+type diffOp struct {
+	kind byte
+	line string
+}
+
+// UnifiedDiff returns a unified diff that turns a into b, or an empty string if they are equal.
+// The names are used in the header lines. An error is returned if the texts are too large to compare.
+func UnifiedDiff(aName, bName, a, b string) (string, error) {
+	if a == b {
+		return "", nil
+	}
+
+	aLines, bLines := splitLines(a), splitLines(b)
+
+	if (len(aLines)+1)*(len(bLines)+1) > maxDiffCells {
+		return "", fmt.Errorf("too large to diff")
+	}
+
+	ops := diffLines(aLines, bLines)
+
+	var sb strings.Builder
+
+	fmt.Fprintf(&sb, "--- %s\n+++ %s\n", aName, bName)
+
+	// aPos and bPos are the numbers of lines of a and b before each operation
+	aPos, bPos := make([]int, len(ops)+1), make([]int, len(ops)+1)
+
+	for i, op := range ops {
+		aPos[i+1], bPos[i+1] = aPos[i], bPos[i]
+
+		if op.kind != '+' {
+			aPos[i+1]++
+		}
+
+		if op.kind != '-' {
+			bPos[i+1]++
+		}
+	}
+
+	for i := 0; i < len(ops); {
+		if ops[i].kind == ' ' {
+			i++
+			continue
+		}
+
+		// Extend the hunk while the next change is close enough to share the context lines
+		end := i
+		for j := i; j < len(ops) && j <= end+2*diffContextLines; j++ {
+			if ops[j].kind != ' ' {
+				end = j
+			}
+		}
+
+		start := max(0, i-diffContextLines)
+		stop := min(len(ops), end+diffContextLines+1)
+
+		fmt.Fprintf(&sb, "@@ -%s +%s @@\n", hunkRange(aPos[start], aPos[stop]-aPos[start]), hunkRange(bPos[start], bPos[stop]-bPos[start]))
+
+		for _, op := range ops[start:stop] {
+			fmt.Fprintf(&sb, "%c%s\n", op.kind, op.line)
+		}
+
+		i = stop
+	}
+
+	return sb.String(), nil
+}
+
+// hunkRange formats the line range of a hunk, given the number of lines before it and its length.
+func hunkRange(before, length int) string {
+	if length == 0 {
+		return fmt.Sprintf("%d,0", before)
+	}
+
+	if length == 1 {
+		return fmt.Sprintf("%d", before+1)
+	}
+
+	return fmt.Sprintf("%d,%d", before+1, length)
+}
+
+// splitLines splits a text into lines without the line breaks.
+func splitLines(s string) []string {
+	if s == "" {
+		return nil
+	}
+
+	return strings.Split(strings.TrimSuffix(s, "\n"), "\n")
+}
+
+// diffLines computes an edit script from a to b based on their longest common subsequence.
+func diffLines(a, b []string) []diffOp {
+	// lcs[i][j] is the length of the longest common subsequence of a[i:] and b[j:]
+	lcs := make([][]int, len(a)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(b)+1)
+	}
+
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+
+	ops := make([]diffOp, 0, max(len(a), len(b)))
+
+	i, j := 0, 0
+	for i < len(a) && j < len(b) {
+		switch {
+		case a[i] == b[j]:
+			ops = append(ops, diffOp{kind: ' ', line: a[i]})
+			i++
+			j++
+		case lcs[i+1][j] >= lcs[i][j+1]:
+			ops = append(ops, diffOp{kind: '-', line: a[i]})
+			i++
+		default:
+			ops = append(ops, diffOp{kind: '+', line: b[j]})
+			j++
+		}
+	}
+
+	for ; i < len(a); i++ {
+		ops = append(ops, diffOp{kind: '-', line: a[i]})
+	}
+
+	for ; j < len(b); j++ {
+		ops = append(ops, diffOp{kind: '+', line: b[j]})
+	}
+
+	return ops
+}
@@ -0,0 +1,184 @@
+package util
+
+import (
+	"bufio"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// AirflowIgnoreFile is the name of the file that lists DAG folder paths Airflow should ignore.
+const AirflowIgnoreFile = ".airflowignore"
+
+// PathFilter selects the files of a directory tree by include and exclude globs and, optionally,
+// the .airflowignore files found in the tree.
+type PathFilter struct {
+	include []string
+	exclude []string
+	ignores map[string][]*regexp.Regexp // The .airflowignore patterns by slash-separated directory, "" for the root.
+}
+
+// NewPathFilter creates a PathFilter for the directory tree at root.
+// Globs without a slash are matched against every path segment, all others against the path relative to root.
+// If airflowIgnore is set, the .airflowignore files of the tree are applied as well. They use the default regexp
+// syntax of Airflow: a path is ignored if any pattern matches a part of its path relative to the directory
+// containing the file.
+func NewPathFilter(root string, include, exclude []string, airflowIgnore bool) (*PathFilter, error) {
+	for _, pattern := range append(append([]string{}, include...), exclude...) {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return nil, fmt.Errorf("invalid glob pattern %q: %w", pattern, err)
+		}
+	}
+
+	f := &PathFilter{
+		include: include,
+		exclude: exclude,
+		ignores: map[string][]*regexp.Regexp{},
+	}
+
+	if !airflowIgnore {
+		return f, nil
+	}
+
+	err := filepath.WalkDir(root, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if d.IsDir() || d.Name() != AirflowIgnoreFile {
+			return nil
+		}
+
+		patterns, err := parseAirflowIgnore(p)
+		if err != nil {
+			return err
+		}
+
+		dir, err := filepath.Rel(root, filepath.Dir(p))
+		if err != nil {
+			return err
+		}
+
+		if dir == "." {
+			dir = ""
+		}
+
+		f.ignores[filepath.ToSlash(dir)] = patterns
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return f, nil
+}
+
+// parseAirflowIgnore reads the regexp patterns of an .airflowignore file, skipping blank lines and comments.
+func parseAirflowIgnore(filePath string) ([]*regexp.Regexp, error) {
+	file, err := os.Open(filePath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open %s: %w", filePath, err)
+	}
+	defer file.Close()
+
+	var patterns []*regexp.Regexp
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line, _, _ := strings.Cut(scanner.Text(), "#")
+
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+
+		re, err := regexp.Compile(line)
+		if err != nil {
+			return nil, fmt.Errorf("invalid pattern %q in %s: %w", line, filePath, err)
+		}
+
+		patterns = append(patterns, re)
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", filePath, err)
+	}
+
+	return patterns, nil
+}
+
+// Match reports whether the file at the slash-separated path relative to the root is selected.
+// A file is selected if neither it nor one of its parent directories is excluded or ignored,
+// and, if include globs are set, it matches one of them.
+func (f *PathFilter) Match(relPath string) bool {
+	return f.match(relPath, true)
+}
+
+// Ignored reports whether the file at the slash-separated path relative to the root is only rejected
+// because of an .airflowignore file, i.e. it would be selected without the .airflowignore files.
+func (f *PathFilter) Ignored(relPath string) bool {
+	return f.match(relPath, false) && !f.match(relPath, true)
+}
+
+// match implements Match, optionally without applying the .airflowignore files.
+func (f *PathFilter) match(relPath string, airflowIgnore bool) bool {
+	segments := strings.Split(relPath, "/")
+
+	for i := range segments {
+		prefix := strings.Join(segments[:i+1], "/")
+
+		if matchAnyGlob(f.exclude, prefix) || (airflowIgnore && f.airflowIgnored(prefix)) {
+			return false
+		}
+	}
+
+	if len(f.include) == 0 {
+		return true
+	}
+
+	return matchAnyGlob(f.include, relPath)
+}
+
+// airflowIgnored reports whether the path is matched by an .airflowignore file of a parent directory.
+func (f *PathFilter) airflowIgnored(relPath string) bool {
+	for dir, patterns := range f.ignores {
+		rel := relPath
+
+		if dir != "" {
+			if !strings.HasPrefix(relPath, dir+"/") {
+				continue
+			}
+
+			rel = strings.TrimPrefix(relPath, dir+"/")
+		}
+
+		for _, re := range patterns {
+			if re.MatchString(rel) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// matchAnyGlob reports whether the path matches one of the globs.
+func matchAnyGlob(patterns []string, relPath string) bool {
+	for _, pattern := range patterns {
+		name := relPath
+		if !strings.Contains(pattern, "/") {
+			name = path.Base(relPath)
+		}
+
+		if ok, _ := path.Match(pattern, name); ok {
+			return true
+		}
+	}
+
+	return false
+}
@@ -10,6 +10,7 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"io/fs"
 	"net"
 	"os"
 	"os/exec"
@@ -17,6 +18,7 @@ import (
 	"regexp"
 	"runtime"
 	"strings"
+	"time"
 )
 
 // OpenBrowser attempts to open the given URL in the default web browser based on the operating system.
@@ -226,6 +228,75 @@ func Unzip(data []byte, dest string) error {
 	return nil
 }
 
+// zipModTime is the modification time of all zip entries written by ZipDirectory.
+// It is the earliest time the zip format can represent.
+var zipModTime = time.Date(1980, 1, 1, 0, 0, 0, 0, time.UTC)
+
+// ZipDirectory writes the files of a directory to w as a zip archive with paths relative to the directory.
+// Entries are written in lexical order with a fixed timestamp and normalized permissions, so identical
+// directory contents always produce an identical archive.
+func ZipDirectory(srcDir string, w io.Writer) error {
+	zw := zip.NewWriter(w)
+
+	err := filepath.WalkDir(srcDir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if d.IsDir() {
+			return nil
+		}
+
+		relPath, err := filepath.Rel(srcDir, path)
+		if err != nil {
+			return err
+		}
+
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+
+		if !info.Mode().IsRegular() {
+			return fmt.Errorf("unsupported file type: %s", path)
+		}
+
+		mode := fs.FileMode(0644)
+		if info.Mode()&0111 != 0 {
+			mode = 0755
+		}
+
+		header := &zip.FileHeader{
+			Name:     filepath.ToSlash(relPath),
+			Method:   zip.Deflate,
+			Modified: zipModTime,
+		}
+		header.SetMode(mode)
+
+		entry, err := zw.CreateHeader(header)
+		if err != nil {
+			return fmt.Errorf("failed to add %s to zip: %w", relPath, err)
+		}
+
+		file, err := os.Open(path)
+		if err != nil {
+			return fmt.Errorf("failed to open file %s: %w", path, err)
+		}
+		defer file.Close()
+
+		if _, err := io.Copy(entry, file); err != nil {
+			return fmt.Errorf("failed to add %s to zip: %w", relPath, err)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	return zw.Close()
+}
+
 // StripNonPrintable removes non-printable characters from a string.
 func StripNonPrintable(input string) string {
 	// Match printable ASCII characters (32-126), newline (10), and tab (9)
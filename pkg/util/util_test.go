@@ -1,8 +1,12 @@
 package util
 
 import (
+	"bytes"
+	"os"
+	"path/filepath"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 )
@@ -230,3 +234,126 @@ func TestStripNonPrintable(t *testing.T) {
 		})
 	}
 }
+
+func TestPathFilter(t *testing.T) {
+	root := t.TempDir()
+
+	assert.NoError(t, os.MkdirAll(filepath.Join(root, "team", "tests"), 0755))
+	assert.NoError(t, os.WriteFile(filepath.Join(root, AirflowIgnoreFile), []byte("# local experiments\nscratch_.*\\.py\n"), 0600))
+	assert.NoError(t, os.WriteFile(filepath.Join(root, "team", AirflowIgnoreFile), []byte("^tests\n"), 0600))
+
+	filter, err := NewPathFilter(root, nil, []string{"__pycache__", "*.pyc"}, true)
+	assert.NoError(t, err)
+
+	noIgnoreFilter, err := NewPathFilter(root, nil, []string{"__pycache__", "*.pyc"}, false)
+	assert.NoError(t, err)
+
+	tests := []struct {
+		relPath  string
+		expected bool
+		ignored  bool
+	}{
+		{relPath: "example_dag.py", expected: true},
+		{relPath: ".airflowignore", expected: true},
+		{relPath: "__pycache__/example_dag.cpython-311.pyc", expected: false},
+		{relPath: "team/__pycache__/helpers.cpython-311.pyc", expected: false},
+		{relPath: "team/helpers.pyc", expected: false},
+		{relPath: "scratch_dag.py", expected: false, ignored: true},
+		{relPath: "team/scratch_dag.py", expected: false, ignored: true},
+		{relPath: "team/tests/test_dag.py", expected: false, ignored: true},
+		{relPath: "team/tests/__pycache__/test_dag.cpython-311.pyc", expected: false},
+		{relPath: "tests/test_dag.py", expected: true},
+		{relPath: "team/dag.py", expected: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.relPath, func(t *testing.T) {
+			assert.Equal(t, tt.expected, filter.Match(tt.relPath))
+			assert.Equal(t, tt.ignored, filter.Ignored(tt.relPath))
+			assert.Equal(t, tt.expected || tt.ignored, noIgnoreFilter.Match(tt.relPath))
+			assert.False(t, noIgnoreFilter.Ignored(tt.relPath))
+		})
+	}
+}
+
+func TestPathFilterInclude(t *testing.T) {
+	filter, err := NewPathFilter(t.TempDir(), []string{"*.py", "sql/*"}, nil, true)
+	assert.NoError(t, err)
+
+	assert.True(t, filter.Match("dags/example_dag.py"))
+	assert.True(t, filter.Match("sql/query.sql"))
+	assert.False(t, filter.Match("README.md"))
+	assert.False(t, filter.Match("dags/sql/query.sql"))
+
+	_, err = NewPathFilter(t.TempDir(), []string{"[invalid"}, nil, true)
+	assert.Error(t, err)
+}
+
+func TestZipDirectory(t *testing.T) {
+	src := t.TempDir()
+
+	assert.NoError(t, os.MkdirAll(filepath.Join(src, "operators"), 0755))
+	assert.NoError(t, os.WriteFile(filepath.Join(src, "__init__.py"), []byte(""), 0600))
+	assert.NoError(t, os.WriteFile(filepath.Join(src, "operators", "custom.py"), []byte("class CustomOperator: pass\n"), 0600))
+
+	var first bytes.Buffer
+	assert.NoError(t, ZipDirectory(src, &first))
+
+	// Touching the files must not change the archive
+	later := time.Now().Add(time.Hour)
+	assert.NoError(t, os.Chtimes(filepath.Join(src, "__init__.py"), later, later))
+
+	var second bytes.Buffer
+	assert.NoError(t, ZipDirectory(src, &second))
+	assert.Equal(t, first.Bytes(), second.Bytes())
+
+	dest := t.TempDir()
+	assert.NoError(t, Unzip(first.Bytes(), dest))
+
+	content, err := os.ReadFile(filepath.Join(dest, "operators", "custom.py"))
+	assert.NoError(t, err)
+	assert.Equal(t, "class CustomOperator: pass\n", string(content))
+}
+
+func TestUnifiedDiff(t *testing.T) {
+	t.Run("Equal", func(t *testing.T) {
+		diff, err := UnifiedDiff("a", "b", "same\n", "same\n")
+		assert.NoError(t, err)
+		assert.Empty(t, diff)
+	})
+
+	t.Run("Separate hunks", func(t *testing.T) {
+		a := "1\n2\n3\n4\n5\n6\n7\n8\n9\n10\n11\n12\n"
+		b := "1\ntwo\n3\n4\n5\n6\n7\n8\n9\n10\n11\n12\n13\n"
+
+		diff, err := UnifiedDiff("remote/dag.py", "local/dag.py", a, b)
+		assert.NoError(t, err)
+		assert.Equal(t, `--- remote/dag.py
++++ local/dag.py
+@@ -1,5 +1,5 @@
+ 1
+-2
++two
+ 3
+ 4
+ 5
+@@ -10,3 +10,4 @@
+ 10
+ 11
+ 12
++13
+`, diff)
+	})
+
+	t.Run("Merged hunk", func(t *testing.T) {
+		diff, err := UnifiedDiff("a", "b", "1\n2\n3\n4\n", "1\n3\n4\nfive\n")
+		assert.NoError(t, err)
+		assert.Equal(t, "--- a\n+++ b\n@@ -1,4 +1,4 @@\n 1\n-2\n 3\n 4\n+five\n", diff)
+	})
+
+	t.Run("New file", func(t *testing.T) {
+		diff, err := UnifiedDiff("a", "b", "", "new\n")
+		assert.NoError(t, err)
+		assert.Equal(t, "--- a\n+++ b\n@@ -0,0 +1 @@\n+new\n", diff)
+	})
+}